@@ -33,6 +33,7 @@ type AnyApplicationSpec struct {
 	SyncPolicy        SyncPolicySpec        `json:"syncPolicy,omitempty"`
 	PlacementStrategy PlacementStrategySpec `json:"placementStrategy,omitempty"`
 	RecoverStrategy   RecoverStrategySpec   `json:"recoverStrategy,omitempty"`
+	UpgradeStrategy   UpgradeStrategySpec   `json:"upgradeStrategy,omitempty"`
 }
 
 type ApplicationSourceSpec struct {
@@ -50,6 +51,15 @@ type ApplicationSourceHelm struct {
 	Parameters []HelmParameter `json:"parameters,omitempty"`
 	// SkipCrds skips custom resource definition installation step (Helm's --skip-crds)
 	SkipCrds bool `json:"skipCrds,omitempty"`
+	// Verify requires the chart provenance to be verified before it is rendered
+	Verify *ChartVerification `json:"verify,omitempty"`
+}
+
+type ChartVerification struct {
+	// KeyringSecret is the name of a Secret in the application namespace holding the public keyring
+	KeyringSecret string `json:"keyringSecret"`
+	// KeyringKey is the key of the keyring in the Secret, keyring.gpg by default
+	KeyringKey string `json:"keyringKey,omitempty"`
 }
 
 type HelmParameter struct {
@@ -106,11 +116,129 @@ type SyncPolicyAutomated struct {
 
 type PlacementStrategySpec struct {
 	Strategy PlacementStrategy `json:"strategy"`
+	// Scorer ranks the zones of the Global strategy, LeastLoaded by default
+	Scorer PlacementScorerType `json:"scorer,omitempty"`
+	// Zones lists the zones of the Static scorer in order of preference
+	Zones []string `json:"zones,omitempty"`
+	// Affinity selects and prefers zones of the Affinity scorer
+	Affinity *ZoneAffinity `json:"affinity,omitempty"`
+}
+
+type ZoneAffinity struct {
+	// Labels are required on a zone for the application to be placed in it
+	Labels map[string]string `json:"labels,omitempty"`
+	// Regions are preferred in the given order, the region of a zone is its region label
+	Regions []string `json:"regions,omitempty"`
 }
 
 type RecoverStrategySpec struct {
 	Tolerance  int `json:"tolerance,omitempty"`
 	MaxRetries int `json:"maxRetries,omitempty"`
+	// Cooldown is how long a zone waits after its retries are exhausted before they start over,
+	// the failure cool-down of the controller by default
+	Cooldown metav1.Duration `json:"cooldown,omitempty"`
+	// HealthCheck configures how long a zone tolerates an unhealthy application before it fails
+	HealthCheck HealthCheckSpec `json:"healthCheck,omitempty"`
+	// Actions remediate an application which fails its health check or misses resources. They
+	// are applied in order, each up to its limit, and start over once the application is healthy.
+	Actions []RecoverAction `json:"actions,omitempty"`
+}
+
+const DefaultRecoverActionLimit = 1
+
+type RecoverAction struct {
+	Type RecoverActionType `json:"type"`
+	// Limit is the number of attempts of the action before the next action is applied, 1 by default
+	Limit int `json:"limit,omitempty"`
+}
+
+// GetLimit returns the number of attempts of the action
+func (a *RecoverAction) GetLimit() int {
+	if a.Limit <= 0 {
+		return DefaultRecoverActionLimit
+	}
+	return a.Limit
+}
+
+// HealthCheckSpec configures the hysteresis of the health check. A Degraded or Unknown application
+// fails once it is unhealthy for FailureThreshold consecutive polls or for the GracePeriod, whichever
+// comes first. It fails on the first unhealthy poll when neither is set.
+type HealthCheckSpec struct {
+	// FailureThreshold is the number of consecutive unhealthy polls before the application fails
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	// GracePeriod is how long the application may stay unhealthy before it fails
+	GracePeriod metav1.Duration `json:"gracePeriod,omitempty"`
+	// ProgressDeadline is how long the application may stay Progressing before it fails,
+	// unlimited by default
+	ProgressDeadline metav1.Duration `json:"progressDeadline,omitempty"`
+}
+
+// ResetFailuresAnnotation requests a reset of the failures recorded before the RFC 3339 timestamp
+// in its value. Zones retry failed deployments and undeployments from the first attempt.
+const ResetFailuresAnnotation = "dcp.hiro.io/reset-failures"
+
+type UpgradeStrategySpec struct {
+	// Type is the way a new chart version replaces the deployed one, InPlace by default
+	Type UpgradeStrategyType `json:"type,omitempty"`
+	// BlueGreen configures the BlueGreen upgrade strategy
+	BlueGreen *BlueGreenSpec `json:"blueGreen,omitempty"`
+	// Rollout configures the waves in which the owner upgrades the placed zones
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+}
+
+// RolloutSpec configures the progressive rollout of a new chart version across the placements.
+// The canary zone is upgraded first, the remaining zones follow in batches once the
+// previously upgraded zones report healthy.
+type RolloutSpec struct {
+	// CanaryZone is the zone upgraded first, the first placement by default
+	CanaryZone string `json:"canaryZone,omitempty"`
+	// BatchSize is the number of zones upgraded in every wave after the canary, 1 by default
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+const DefaultRolloutBatchSize = 1
+
+// BlueGreenSpec configures upgrades which deploy the new version next to the old one.
+// Every version is released under a version-suffixed release name.
+type BlueGreenSpec struct {
+	// Services are the names of Services shared by the versions. Their selector is switched
+	// to the new version once it is healthy, so they need a name independent of the release name.
+	Services []string `json:"services,omitempty"`
+	// SelectorLabel is the selector key of the Services which selects pods of a release,
+	// app.kubernetes.io/instance by default
+	SelectorLabel string `json:"selectorLabel,omitempty"`
+	// BakeTime is how long the old version is kept after the switch before it is cleaned up
+	BakeTime metav1.Duration `json:"bakeTime,omitempty"`
+}
+
+const DefaultBlueGreenSelectorLabel = "app.kubernetes.io/instance"
+
+func (s *UpgradeStrategySpec) IsBlueGreen() bool {
+	return s.Type == UpgradeStrategyBlueGreen
+}
+
+// GetBlueGreen returns the BlueGreen configuration with defaults applied
+func (s *UpgradeStrategySpec) GetBlueGreen() BlueGreenSpec {
+	blueGreen := BlueGreenSpec{}
+	if s.BlueGreen != nil {
+		s.BlueGreen.DeepCopyInto(&blueGreen)
+	}
+	if blueGreen.SelectorLabel == "" {
+		blueGreen.SelectorLabel = DefaultBlueGreenSelectorLabel
+	}
+	return blueGreen
+}
+
+// GetRollout returns the Rollout configuration with defaults applied
+func (s *UpgradeStrategySpec) GetRollout() RolloutSpec {
+	rollout := RolloutSpec{}
+	if s.Rollout != nil {
+		rollout = *s.Rollout
+	}
+	if rollout.BatchSize <= 0 {
+		rollout.BatchSize = DefaultRolloutBatchSize
+	}
+	return rollout
 }
 
 // AnyApplicationStatus defines the observed state of AnyApplication.
@@ -124,6 +252,32 @@ type OwnershipStatus struct {
 	State      GlobalState `json:"state"`
 	Owner      string      `json:"owner"`
 	Placements []Placement `json:"placements,omitempty"`
+	// TargetVersion is the chart version the owner resolved from the version range of the source.
+	// Placement zones deploy this version instead of resolving the range themselves.
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// Rollout is the chart version the owner rolls out across the placements
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+// RolloutStatus records the chart version chosen by the owner and the zones admitted to it.
+// Zones which are not admitted yet keep the previous version until the rollout completes.
+type RolloutStatus struct {
+	Version         string       `json:"version"`
+	PreviousVersion string       `json:"previousVersion,omitempty"`
+	Phase           RolloutPhase `json:"phase"`
+	// Wave is the number of the current wave, the canary is wave 1
+	Wave int `json:"wave,omitempty"`
+	// Zones are the zones admitted to the version, in the order of the waves
+	Zones   []string `json:"zones,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// TargetVersion returns the chart version the zone deploys during the rollout
+func (r *RolloutStatus) TargetVersion(zone string) string {
+	if r.Phase != RolloutPhaseCompleted && r.PreviousVersion != "" && !lo.Contains(r.Zones, zone) {
+		return r.PreviousVersion
+	}
+	return r.Version
 }
 
 type ZoneStatus struct {
@@ -131,11 +285,21 @@ type ZoneStatus struct {
 	ZoneVersion  int64             `json:"version"`
 	ChartVersion string            `json:"chartVersion,omitempty"`
 	Conditions   []ConditionStatus `json:"conditions,omitempty"`
+	// LastHeartbeatTime is refreshed periodically by the zone while it reports the status
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// LastResetTime acknowledges the last reset of failures requested through the annotation.
+	// The zone cleared the failures and retry counters recorded before it.
+	LastResetTime *metav1.Time `json:"lastResetTime,omitempty"`
 }
 
 type Placement struct {
-	Zone         string   `json:"zone"`
+	Zone string `json:"zone"`
+	// NodeAffinity pins the application to nodes of the zone.
+	// Entries in the form key=value select nodes by label, other entries are node names.
 	NodeAffinity []string `json:"nodeAffinity,omitempty"`
+	// Replaces is the zone this placement takes over from during a relocation.
+	// The replaced zone keeps the application until this zone reports it healthy.
+	Replaces string `json:"replaces,omitempty"`
 }
 
 type ConditionStatus struct {
@@ -16,6 +16,9 @@ const (
 	OwnershipTransferConditionType ApplicationConditionType = "OwnershipTransfer"
 	DeploymentConditionType        ApplicationConditionType = "Deployment"
 	UndeploymentConditionType      ApplicationConditionType = "Undeployment"
+	RelocationConditionType        ApplicationConditionType = "Relocation"
+	TargetVersionConditionType     ApplicationConditionType = "TargetVersion"
+	RemediationConditionType       ApplicationConditionType = "Remediation"
 )
 
 func (s *ApplicationConditionType) UnmarshalJSON(data []byte) error {
@@ -29,7 +32,10 @@ func (s *ApplicationConditionType) UnmarshalJSON(data []byte) error {
 		string(PlacementConditionType),
 		string(OwnershipTransferConditionType),
 		string(DeploymentConditionType),
-		string(UndeploymentConditionType):
+		string(UndeploymentConditionType),
+		string(RelocationConditionType),
+		string(TargetVersionConditionType),
+		string(RemediationConditionType):
 		*s = ApplicationConditionType(str)
 		return nil
 	default:
@@ -155,3 +161,98 @@ func (s *UndeploymentStatus) UnmarshalJSON(data []byte) error {
 func (s UndeploymentStatus) MarshalJSON() ([]byte, error) {
 	return json.Marshal(string(s))
 }
+
+type RelocationStatus string
+
+const (
+	RelocationStatusInProgress RelocationStatus = "InProgress"
+	RelocationStatusDone       RelocationStatus = "Done"
+	RelocationStatusRolledBack RelocationStatus = "RolledBack"
+)
+
+func (s *RelocationStatus) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	switch str {
+	case string(RelocationStatusInProgress),
+		string(RelocationStatusDone),
+		string(RelocationStatusRolledBack):
+		*s = RelocationStatus(str)
+		return nil
+	default:
+		return errors.New("invalid RelocationStatus: " + str)
+	}
+}
+
+func (s RelocationStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+type TargetVersionStatus string
+
+const (
+	// TargetVersionStatusUnavailable means the zone cannot fetch the chart version published by the owner
+	TargetVersionStatusUnavailable TargetVersionStatus = "Unavailable"
+	// TargetVersionStatusAvailable means the zone fetched the published version after it was unavailable
+	TargetVersionStatusAvailable TargetVersionStatus = "Available"
+)
+
+func (s *TargetVersionStatus) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	switch str {
+	case string(TargetVersionStatusUnavailable),
+		string(TargetVersionStatusAvailable):
+		*s = TargetVersionStatus(str)
+		return nil
+	default:
+		return errors.New("invalid TargetVersionStatus: " + str)
+	}
+}
+
+func (s TargetVersionStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// RemediationStatus is the outcome of the last recover action of a zone, the reason of the
+// condition names the action
+type RemediationStatus string
+
+const (
+	// RemediationStatusAttempted means the zone applied the recover action
+	RemediationStatusAttempted RemediationStatus = "Attempted"
+	// RemediationStatusFailed means the recover action could not be applied
+	RemediationStatusFailed RemediationStatus = "Failed"
+	// RemediationStatusExhausted means the zone applied all recover actions up to their limits
+	RemediationStatusExhausted RemediationStatus = "Exhausted"
+	// RemediationStatusRecovered means the application became healthy after the recover actions
+	RemediationStatusRecovered RemediationStatus = "Recovered"
+)
+
+func (s *RemediationStatus) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	switch str {
+	case string(RemediationStatusAttempted),
+		string(RemediationStatusFailed),
+		string(RemediationStatusExhausted),
+		string(RemediationStatusRecovered):
+		*s = RemediationStatus(str)
+		return nil
+	default:
+		return errors.New("invalid RemediationStatus: " + str)
+	}
+}
+
+func (s RemediationStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
@@ -34,3 +34,138 @@ func (s *PlacementStrategy) UnmarshalJSON(data []byte) error {
 func (s PlacementStrategy) MarshalJSON() ([]byte, error) {
 	return json.Marshal(string(s))
 }
+
+// RegionLabel is the zone label holding the region of the zone
+const RegionLabel = "region"
+
+type PlacementScorerType string
+
+const (
+	PlacementScorerLeastLoaded PlacementScorerType = "LeastLoaded"
+	PlacementScorerAffinity    PlacementScorerType = "Affinity"
+	PlacementScorerSpread      PlacementScorerType = "Spread"
+	PlacementScorerStatic      PlacementScorerType = "Static"
+)
+
+func (s *PlacementScorerType) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	switch str {
+	case "",
+		string(PlacementScorerLeastLoaded),
+		string(PlacementScorerAffinity),
+		string(PlacementScorerSpread),
+		string(PlacementScorerStatic):
+		*s = PlacementScorerType(str)
+		return nil
+	default:
+		return errors.New("invalid placement scorer: " + str)
+	}
+}
+
+func (s PlacementScorerType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+type UpgradeStrategyType string
+
+const (
+	// UpgradeStrategyInPlace syncs the new version over the deployed one and prunes leftovers afterwards
+	UpgradeStrategyInPlace UpgradeStrategyType = "InPlace"
+	// UpgradeStrategyRecreate undeploys the deployed version before the new version is deployed
+	UpgradeStrategyRecreate UpgradeStrategyType = "Recreate"
+	// UpgradeStrategyBlueGreen deploys the new version next to the deployed one and switches traffic once it is healthy
+	UpgradeStrategyBlueGreen UpgradeStrategyType = "BlueGreen"
+)
+
+func (s *UpgradeStrategyType) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	switch str {
+	case "",
+		string(UpgradeStrategyInPlace),
+		string(UpgradeStrategyRecreate),
+		string(UpgradeStrategyBlueGreen):
+		*s = UpgradeStrategyType(str)
+		return nil
+	default:
+		return errors.New("invalid upgrade strategy: " + str)
+	}
+}
+
+func (s UpgradeStrategyType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+type RolloutPhase string
+
+const (
+	// RolloutPhaseProgressing means zones are admitted to the version wave by wave
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhaseHalted means an admitted zone failed and no further zones are admitted
+	RolloutPhaseHalted RolloutPhase = "Halted"
+	// RolloutPhaseCompleted means all zones deploy the version
+	RolloutPhaseCompleted RolloutPhase = "Completed"
+)
+
+func (s *RolloutPhase) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	switch str {
+	case string(RolloutPhaseProgressing),
+		string(RolloutPhaseHalted),
+		string(RolloutPhaseCompleted):
+		*s = RolloutPhase(str)
+		return nil
+	default:
+		return errors.New("invalid rollout phase: " + str)
+	}
+}
+
+func (s RolloutPhase) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+type RecoverActionType string
+
+const (
+	// RecoverActionResync syncs the missing or drifted resources of the deployed version in place
+	RecoverActionResync RecoverActionType = "Resync"
+	// RecoverActionRestart restarts the workloads of the deployed version which are not healthy
+	RecoverActionRestart RecoverActionType = "Restart"
+	// RecoverActionRedeploy deletes the resources of the deployed version and deploys it again
+	RecoverActionRedeploy RecoverActionType = "Redeploy"
+	// RecoverActionRelocate asks the owner to relocate the application to another zone
+	RecoverActionRelocate RecoverActionType = "Relocate"
+)
+
+func (s *RecoverActionType) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	switch str {
+	case string(RecoverActionResync),
+		string(RecoverActionRestart),
+		string(RecoverActionRedeploy),
+		string(RecoverActionRelocate):
+		*s = RecoverActionType(str)
+		return nil
+	default:
+		return errors.New("invalid recover action: " + str)
+	}
+}
+
+func (s RecoverActionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
@@ -22,6 +22,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/go-logr/logr"
 	"go.uber.org/zap/zapcore"
 	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
@@ -39,6 +41,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	configctrl "sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -55,12 +58,17 @@ import (
 	"hiro.io/anyapplication/internal/controller/events"
 	"hiro.io/anyapplication/internal/controller/job"
 	"hiro.io/anyapplication/internal/controller/reconciler"
+	"hiro.io/anyapplication/internal/controller/status"
 	"hiro.io/anyapplication/internal/controller/sync"
 	"hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/errorctx"
 	"hiro.io/anyapplication/internal/helm"
 	"hiro.io/anyapplication/internal/httpapi"
+	"hiro.io/anyapplication/internal/notification"
+	"hiro.io/anyapplication/internal/peers"
+	"hiro.io/anyapplication/internal/podtemplate"
 	"hiro.io/anyapplication/internal/resources"
+	"hiro.io/anyapplication/internal/restmapping"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -222,8 +230,13 @@ func main() {
 	// config := ctrl.GetConfigOrDie()
 	config, err := configctrl.GetConfigWithContext(applicationConfig.ZoneId)
 	failIfError(err, setupLog, "unable to get config")
+	restMapper, err := restmapping.NewCachedMapper(config, loggers["Helm"])
+	failIfError(err, setupLog, "unable to create REST mapper")
 	mgr, err := ctrl.NewManager(config, ctrl.Options{
-		Scheme:                 scheme,
+		Scheme: scheme,
+		MapperProvider: func(*rest.Config, *http.Client) (meta.RESTMapper, error) {
+			return restMapper.RESTMapper(), nil
+		},
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
@@ -244,19 +257,36 @@ func main() {
 	failIfError(err, setupLog, "unable to start manager")
 
 	kubeClient := mgr.GetClient()
+	var kubeVersion *chartutil.KubeVersion
+	if controllerConfig.Helm.KubeVersion != "" {
+		kubeVersion, err = chartutil.ParseKubeVersion(controllerConfig.Helm.KubeVersion)
+		failIfError(err, setupLog, "invalid kube version override")
+	}
+	podTemplates, err := podtemplate.NewRegistry(applicationConfig.PodTemplates)
+	failIfError(err, setupLog, "invalid pod template configuration")
+	postProcessors := helm.NewPostProcessors(
+		&controllerConfig.Helm.PostProcessing, applicationConfig.ZoneId, podTemplates, loggers["Helm"],
+	)
 	helmClient, err := helm.NewHelmClient(&helm.HelmClientOptions{
-		RestConfig: config,
-		Debug:      false,
-		Linting:    true,
-		KubeVersion: &chartutil.KubeVersion{
-			Version: "v1.23.10",
-			Major:   "1",
-			Minor:   "23",
-		},
-		ClientId: applicationConfig.ZoneId,
-		Log:      loggers["Helm"],
+		RestConfig:     config,
+		Debug:          false,
+		Linting:        true,
+		KubeVersion:    kubeVersion,
+		APIVersions:    controllerConfig.Helm.APIVersions,
+		ClientId:       applicationConfig.ZoneId,
+		MirrorDir:      controllerConfig.Helm.MirrorDir,
+		ScopeMapper:    restMapper,
+		PodTemplates:   podTemplates,
+		PostProcessors: postProcessors,
+		Log:            loggers["Helm"],
 	})
 	failIfError(err, setupLog, "unable to create helm client")
+	if err := helmClient.RefreshCapabilities(); err != nil {
+		setupLog.Error(err, "unable to discover cluster capabilities")
+	}
+	if controllerConfig.Helm.CapabilitiesRefreshInterval > 0 {
+		go helmClient.RunCapabilitiesRefresh(context.Background(), controllerConfig.Helm.CapabilitiesRefreshInterval)
+	}
 
 	clock := clock.NewClock()
 	resourceExcludes := controllerConfig.Cache.ExcludesSet()
@@ -277,16 +307,29 @@ func main() {
 		}),
 		cache.SetSettings(cacheSettings),
 	)
+	restMapper.InvalidateOnCRDChanges(clusterCache)
 	gitOpsEngine := engine.NewEngine(config, clusterCache, engine.WithLogr(loggers["GitOpsEngine"]))
 	stopFunc, err := gitOpsEngine.Run()
 	failIfError(err, setupLog, "unable to start gitops engine")
 
 	charts := sync.NewCharts(context.Background(), helmClient, &sync.ChartsOptions{
-		SyncPeriod: controllerConfig.Runtime.ChartVersionPollInterval,
+		SyncPeriod:   controllerConfig.Runtime.ChartVersionPollInterval,
+		PodTemplates: podTemplates,
 	}, loggers["SyncManager"])
 
 	go charts.RunSynchronization()
 
+	peerClient := peers.NewPeerClient(
+		controllerConfig.Peers,
+		&controllerConfig.Peering,
+		applicationConfig.ZoneId,
+		applicationConfig.ZoneLabels,
+		kubeClient,
+		clock,
+		loggers["Peers"],
+	)
+	go peerClient.Run(context.Background())
+
 	applications := sync.NewApplications(
 		kubeClient,
 		helmClient,
@@ -294,16 +337,24 @@ func main() {
 		clusterCache,
 		clock,
 		&applicationConfig,
+		peerClient,
 		gitOpsEngine,
 		loggers["SyncManager"],
 	)
 
 	jobContext := job.NewAsyncJobContext(helmClient, kubeClient, context.Background(), applications)
 	jobs := job.NewJobs(jobContext)
-	events := events.NewEvents(mgr.GetEventRecorderFor("Controller"))
+	notifier, err := notification.NewNotifier(&controllerConfig.Notifications, loggers["Controller"])
+	failIfError(err, setupLog, "unable to create notification sinks")
+	events := events.NewEvents(mgr.GetEventRecorderFor("Controller"), notifier, applicationConfig.ZoneId, clock)
 	jobFactory := job.NewAsyncJobFactory(&applicationConfig, clock, loggers["Jobs"], &events)
 	reconciler := reconciler.NewReconciler(jobs, jobFactory)
 
+	heartbeat := status.NewHeartbeat(
+		kubeClient, applicationConfig.ZoneId, applicationConfig.HeartbeatInterval, clock, &events, loggers["Controller"],
+	)
+	go heartbeat.Run(context.Background())
+
 	if err = (&controller.AnyApplicationReconciler{
 		Client:       mgr.GetClient(),
 		Scheme:       mgr.GetScheme(),
@@ -339,9 +390,9 @@ func main() {
 	logFetcher := errorctx.NewRealLogFetcher(clientset)
 	applicationReports := errorctx.NewApplicationReports(clusterCache, logFetcher)
 
-	options := httpapi.ApplicationApiOptions{Address: controllerConfig.Api.BindAddress}
-	applicationSpecs := resources.NewApplicationSpecs(applications, kubeClient, loggers["API"])
-	httpServer := httpapi.NewHttpServer(options, applicationReports, applicationSpecs, &applications, kubeClient)
+	options := httpapi.ApplicationApiOptions{Address: controllerConfig.Api.BindAddress, ZoneId: applicationConfig.ZoneId}
+	applicationSpecs := resources.NewApplicationSpecs(applications, kubeClient, podTemplates, loggers["API"])
+	httpServer := httpapi.NewHttpServer(options, applicationReports, applicationSpecs, &applications, peerClient, kubeClient)
 
 	go func() {
 		if err := httpServer.Start(); err != nil {
@@ -15,23 +15,126 @@ import (
 )
 
 type ApplicationRuntimeConfig struct {
-	ZoneId                        string        `yaml:"zone"`
-	PollOperationalStatusInterval time.Duration `yaml:"operationalPollDuration"`
-	PollSyncStatusInterval        time.Duration `yaml:"syncPollDuration"`
-	ChartVersionPollInterval      time.Duration `yaml:"chartVersionPollDuration"`
-	DefaultSyncTimeout            time.Duration `yaml:"defaultSyncTimeout"`
-	DefaultUndeployTimeout        time.Duration `yaml:"defaultUndeployTimeout"`
+	ZoneId                        string                         `yaml:"zone"`
+	PollOperationalStatusInterval time.Duration                  `yaml:"operationalPollDuration"`
+	PollSyncStatusInterval        time.Duration                  `yaml:"syncPollDuration"`
+	ChartVersionPollInterval      time.Duration                  `yaml:"chartVersionPollDuration"`
+	DefaultSyncTimeout            time.Duration                  `yaml:"defaultSyncTimeout"`
+	DefaultUndeployTimeout        time.Duration                  `yaml:"defaultUndeployTimeout"`
+	RenderCacheSize               int                            `yaml:"renderCacheSize"`
+	RenderCacheDir                string                         `yaml:"renderCacheDir"`
+	ChartVerification             []RepositoryVerificationConfig `yaml:"chartVerification"`
+	PodTemplates                  []PodTemplateConfig            `yaml:"podTemplates"`
+	CapacityCheck                 bool                           `yaml:"capacityCheck"`
+	HeartbeatInterval             time.Duration                  `yaml:"heartbeatInterval"`
+	ZoneStaleThreshold            time.Duration                  `yaml:"zoneStaleThreshold"`
+	RelocationTimeout             time.Duration                  `yaml:"relocationTimeout"`
+	FailureCooldown               time.Duration                  `yaml:"failureCooldown"`
+	ZoneLabels                    map[string]string              `yaml:"zoneLabels"`
+}
+
+// PodTemplateConfig registers the pod template of a custom workload kind.
+type PodTemplateConfig struct {
+	Group string `yaml:"group"`
+	Kind  string `yaml:"kind"`
+	// Path of the pod template within the object, e.g. spec.template
+	Path string `yaml:"path"`
+}
+
+// RepositoryVerificationConfig requires provenance verification of all charts of a repository.
+type RepositoryVerificationConfig struct {
+	Repository             string `yaml:"repository"`
+	KeyringSecretNamespace string `yaml:"keyringSecretNamespace"`
+	KeyringSecretName      string `yaml:"keyringSecretName"`
+	KeyringSecretKey       string `yaml:"keyringSecretKey"`
 }
 
 // Define a struct to match the YAML structure
 type Config struct {
-	Peers []struct {
-		Url string `yaml:"url"`
-	} `yaml:"peers"`
-	Runtime ApplicationRuntimeConfig `yaml:"runtime"`
-	Api     ApiConfig                `yaml:"api"`
-	Cache   CacheConfig              `yaml:"cache"`
-	Logging LoggingConfig            `yaml:"logging"`
+	Peers         []PeerConfig             `yaml:"peers"`
+	Peering       PeeringConfig            `yaml:"peering"`
+	Runtime       ApplicationRuntimeConfig `yaml:"runtime"`
+	Api           ApiConfig                `yaml:"api"`
+	Cache         CacheConfig              `yaml:"cache"`
+	Helm          HelmConfig               `yaml:"helm"`
+	Logging       LoggingConfig            `yaml:"logging"`
+	Notifications NotificationsConfig      `yaml:"notifications"`
+}
+
+type PeerConfig struct {
+	// Url of the API of the controller in the peer zone
+	Url string `yaml:"url"`
+	// Zone of the peer, learned from the health ping when empty
+	Zone string `yaml:"zone"`
+}
+
+type PeeringConfig struct {
+	// PingInterval controls how often peers are pinged
+	PingInterval time.Duration `yaml:"ping_interval"`
+	// Timeout of requests to peers
+	Timeout time.Duration `yaml:"timeout"`
+	// FailureThreshold is the number of consecutive failed pings after which a peer is unreachable
+	FailureThreshold int `yaml:"failure_threshold"`
+}
+
+type NotificationsConfig struct {
+	// Timeout of requests to the sinks
+	Timeout time.Duration `yaml:"timeout"`
+	// Sinks receive the global state changes and the failures of applications
+	Sinks []NotificationSinkConfig `yaml:"sinks"`
+}
+
+type NotificationSinkConfig struct {
+	// Name of the sink in logs
+	Name string `yaml:"name"`
+	// Type of the sink: webhook, cloudevents or slack
+	Type string `yaml:"type"`
+	// Url the notifications are posted to
+	Url string `yaml:"url"`
+	// Headers are added to the requests, e.g. for authorization
+	Headers map[string]string `yaml:"headers"`
+	// WarningsOnly limits the sink to failures
+	WarningsOnly bool `yaml:"warnings_only"`
+}
+
+type HelmConfig struct {
+	// Directory with pre-seeded chart repositories used when the uplink is unavailable
+	MirrorDir string `yaml:"mirror_dir"`
+	// KubeVersion overrides the discovered Kubernetes version exposed to charts
+	KubeVersion string `yaml:"kube_version"`
+	// APIVersions are exposed to charts in addition to the discovered ones
+	APIVersions []string `yaml:"api_versions"`
+	// CapabilitiesRefreshInterval controls how often the cluster capabilities are discovered
+	CapabilitiesRefreshInterval time.Duration `yaml:"capabilities_refresh_interval"`
+	// PostProcessing configures the modifications applied to all rendered resources
+	PostProcessing PostProcessingConfig `yaml:"post_processing"`
+}
+
+type PostProcessingConfig struct {
+	// Annotations are added to all rendered resources
+	Annotations map[string]string `yaml:"annotations"`
+	// ImageMirrors rewrite container images to registry mirrors of a zone
+	ImageMirrors []ImageMirrorConfig `yaml:"image_mirrors"`
+	// Tolerations are added to all pod templates
+	Tolerations []TolerationConfig `yaml:"tolerations"`
+	// PriorityClassName is set on pod templates without a priority class
+	PriorityClassName string `yaml:"priority_class_name"`
+}
+
+type ImageMirrorConfig struct {
+	// Zone the mirror is used in, the mirror is used in all zones when empty
+	Zone string `yaml:"zone"`
+	// Registry of images pulled through the mirror, all registries when empty
+	Registry string `yaml:"registry"`
+	Mirror   string `yaml:"mirror"`
+}
+
+type TolerationConfig struct {
+	Key               string `yaml:"key"`
+	Operator          string `yaml:"operator"`
+	Value             string `yaml:"value"`
+	Effect            string `yaml:"effect"`
+	TolerationSeconds *int64 `yaml:"toleration_seconds"`
 }
 
 type CacheConfig struct {
@@ -19,10 +19,13 @@ package controller
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
@@ -85,7 +88,7 @@ func (r *AnyApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return r.addFinalizer(ctx, resource)
 	}
 
-	globalApplication, err := r.Applications.LoadApplication(resource)
+	globalApplication, err := r.Applications.LoadApplication(ctx, resource)
 	if err != nil {
 		r.Log.Error(err, "failed to load application state")
 		return ctrl.Result{
@@ -213,6 +216,7 @@ func (r *AnyApplicationReconciler) resourceCleanup(ctx context.Context, resource
 		if _, err := r.Applications.Cleanup(ctx, resource); err != nil {
 			return ctrl.Result{}, err
 		}
+		r.Applications.Forget(resource)
 
 		// Remove finalizer and update
 		resource.Finalizers = removeString(resource.Finalizers, AnyApplicationFinalizerName)
@@ -244,6 +248,7 @@ func removeString(slice []string, s string) []string {
 
 func mergeStatus(currentStatus *dcpv1.AnyApplicationStatus, newStatus *dcpv1.AnyApplicationStatus, zone string) (bool, events.Event) {
 	updated := false
+	stateChanged := false
 	reason := events.GlobalStateChangeReason
 	msg := ""
 	if currentStatus.Ownership.Placements == nil && newStatus.Ownership.Placements != nil {
@@ -252,17 +257,39 @@ func mergeStatus(currentStatus *dcpv1.AnyApplicationStatus, newStatus *dcpv1.Any
 		currentStatus.Ownership.Placements = newStatus.Ownership.Placements
 		msg += fmt.Sprintf("Placements are set to '%v'. ", newStatus.Ownership.Placements)
 		updated = true
+	} else if newStatus.Ownership.Epoch > currentStatus.Ownership.Epoch && newStatus.Ownership.Placements != nil {
+		// Placements rewritten by the owner bump the epoch (relocation, scaling)
+		msg += describePlacementChange(currentStatus.Ownership.Placements, newStatus.Ownership.Placements)
+		currentStatus.Ownership.Placements = newStatus.Ownership.Placements
+		msg += fmt.Sprintf("Placements are changed to '%v'. ", newStatus.Ownership.Placements)
+		reason = events.PlacementChangeReason
+		updated = true
 	}
 	if newStatus.Ownership.State != dcpv1.UnknownGlobalState && currentStatus.Ownership.State != newStatus.Ownership.State {
 		currentStatus.Ownership.State = newStatus.Ownership.State
 		msg += fmt.Sprintf("Global state changed to '%s'. ", newStatus.Ownership.State)
 		updated = true
+		stateChanged = true
 	}
 	if currentStatus.Ownership.Owner == "" && newStatus.Ownership.Owner != "" {
 		currentStatus.Ownership.Owner = newStatus.Ownership.Owner
 		msg += fmt.Sprintf("Owner changed to '%s'. ", newStatus.Ownership.Owner)
 		updated = true
 	}
+	if currentStatus.Ownership.Owner == zone && newStatus.Ownership.TargetVersion != "" &&
+		currentStatus.Ownership.TargetVersion != newStatus.Ownership.TargetVersion {
+		currentStatus.Ownership.TargetVersion = newStatus.Ownership.TargetVersion
+		msg += fmt.Sprintf("Target version changed to '%s'. ", newStatus.Ownership.TargetVersion)
+		updated = true
+	}
+	if currentStatus.Ownership.Owner == zone && !reflect.DeepEqual(currentStatus.Ownership.Rollout, newStatus.Ownership.Rollout) {
+		// the rollout is driven by the owner only
+		currentStatus.Ownership.Rollout = newStatus.Ownership.Rollout
+		if rollout := newStatus.Ownership.Rollout; rollout != nil {
+			msg += fmt.Sprintf("Rollout of version '%s' is %s. ", rollout.Version, rollout.Phase)
+		}
+		updated = true
+	}
 	epoch := max(newStatus.Ownership.Epoch, currentStatus.Ownership.Epoch)
 	if currentStatus.Ownership.Epoch != epoch {
 		currentStatus.Ownership.Epoch = epoch
@@ -282,13 +309,27 @@ func mergeStatus(currentStatus *dcpv1.AnyApplicationStatus, newStatus *dcpv1.Any
 			zoneStatus.ChartVersion = newZoneStatus.ChartVersion
 			updated = true
 		}
+		if resetAt := newZoneStatus.LastResetTime; resetAt != nil &&
+			(zoneStatus.LastResetTime == nil || zoneStatus.LastResetTime.Before(resetAt)) {
+			// the zone cleared the failures and retry counters recorded before the reset
+			zoneStatus.Conditions = lo.Filter(zoneStatus.Conditions, func(existing dcpv1.ConditionStatus, _ int) bool {
+				_, kept := newZoneStatus.FindCondition(existing.Type)
+				return kept || !existing.LastTransitionTime.Before(resetAt)
+			})
+			zoneStatus.LastResetTime = resetAt
+			msg += fmt.Sprintf("Failures before '%s' are reset. ", resetAt.UTC().Format(time.RFC3339))
+			if reason == events.GlobalStateChangeReason && !stateChanged {
+				reason = events.FailuresResetReason
+			}
+			updated = true
+		}
 		if newZoneStatus.Conditions != nil {
 			for _, newCondition := range newZoneStatus.Conditions {
 				found := false
 				for i, existingCondition := range zoneStatus.Conditions {
 					if existingCondition.Type == newCondition.Type && existingCondition.ZoneId == newCondition.ZoneId {
 						if existingCondition.LastTransitionTime.Time.Before(newCondition.LastTransitionTime.Time) {
-							if existingCondition.Status != newCondition.Status {
+							if conditionChanged(&existingCondition, &newCondition) {
 								zoneStatus.Conditions[i] = newCondition
 								updated = true
 							}
@@ -306,9 +347,44 @@ func mergeStatus(currentStatus *dcpv1.AnyApplicationStatus, newStatus *dcpv1.Any
 		currentStatus.RemoveZone(zone)
 	}
 	event := events.Event{Reason: reason, Msg: msg}
+	if stateChanged {
+		// the owner drives the global state, it notifies about the changes on behalf of all zones
+		event.Notify = currentStatus.Ownership.Owner == zone
+		if currentStatus.Ownership.State == dcpv1.FailureGlobalState {
+			event.Reason = events.GlobalFailureReason
+			event.Warning = true
+		}
+	}
 	return updated, event
 }
 
+// conditionChanged tells whether the new condition reports something else than the existing one,
+// a new transition time alone is not a change
+func conditionChanged(existing *dcpv1.ConditionStatus, next *dcpv1.ConditionStatus) bool {
+	return existing.Status != next.Status ||
+		existing.Reason != next.Reason ||
+		existing.Msg != next.Msg ||
+		existing.RetryAttempt != next.RetryAttempt
+}
+
+func describePlacementChange(current []dcpv1.Placement, next []dcpv1.Placement) string {
+	zones := func(placements []dcpv1.Placement) []string {
+		return lo.Map(placements, func(placement dcpv1.Placement, _ int) string { return placement.Zone })
+	}
+	added, removed := lo.Difference(zones(next), zones(current))
+	msg := ""
+	if len(current) != len(next) {
+		msg += fmt.Sprintf("Scaled from %d to %d zones. ", len(current), len(next))
+	}
+	if len(added) > 0 {
+		msg += fmt.Sprintf("Zones %s are added. ", strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		msg += fmt.Sprintf("Zones %s are removed. ", strings.Join(removed, ", "))
+	}
+	return msg
+}
+
 func isOwnerOrPlacementZone(resource *dcpv1.AnyApplication, zone string) bool {
 	isOwnerZone := resource.Status.Ownership.Owner == zone
 	isPlacementZone := false
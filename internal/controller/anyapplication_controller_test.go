@@ -44,6 +44,7 @@ import (
 	"hiro.io/anyapplication/internal/controller/sync"
 	ctrltypes "hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/helm"
+	"hiro.io/anyapplication/internal/peers"
 )
 
 var _ = Describe("AnyApplication Controller", func() {
@@ -111,7 +112,7 @@ var _ = Describe("AnyApplication Controller", func() {
 
 			fakeCharts = sync.NewFakeCharts()
 
-			syncManager = sync.NewApplications(k8sClient, helmClient, fakeCharts, clusterCache, clock, &runtimeConfig, gitOpsEngine, logf.Log)
+			syncManager = sync.NewApplications(k8sClient, helmClient, fakeCharts, clusterCache, clock, &runtimeConfig, peers.NewFakeReachability(), gitOpsEngine, logf.Log)
 			jobContext := job.NewAsyncJobContext(helmClient, k8sClient, ctx, syncManager)
 			jobs = job.NewJobs(jobContext)
 			jobFactory := job.NewAsyncJobFactory(&runtimeConfig, clock, logf.Log, &fakeEvents)
@@ -5,26 +5,52 @@ package events
 
 import (
 	dcpv1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
+	"hiro.io/anyapplication/internal/notification"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
 )
 
 type Events struct {
 	recorder record.EventRecorder
+	notifier *notification.Notifier
+	zoneId   string
+	clock    clock.Clock
 }
 
-func NewEvents(recorder record.EventRecorder) Events {
+func NewEvents(recorder record.EventRecorder, notifier *notification.Notifier, zoneId string, clock clock.Clock) Events {
 	return Events{
 		recorder: recorder,
+		notifier: notifier,
+		zoneId:   zoneId,
+		clock:    clock,
 	}
 }
 
 func (e *Events) Emit(application *dcpv1.AnyApplication, event Event) {
-	e.recorder.Event(application, corev1.EventTypeNormal, event.Reason, event.Msg)
+	eventType := corev1.EventTypeNormal
+	if event.Warning {
+		eventType = corev1.EventTypeWarning
+	}
+	e.recorder.Event(application, eventType, event.Reason, event.Msg)
+
+	if event.Notify && e.notifier != nil {
+		e.notifier.Notify(&notification.Notification{
+			Application: application.Name,
+			Namespace:   application.Namespace,
+			Zone:        e.zoneId,
+			GlobalState: string(application.Status.Ownership.State),
+			Warning:     event.Warning,
+			Reason:      event.Reason,
+			Message:     event.Msg,
+			Time:        e.clock.NowTime().Time,
+		})
+	}
 }
 
 func NewFakeEvents() Events {
 	return Events{
 		recorder: NewFakeEventRecorder(),
+		clock:    clock.NewFakeClock(),
 	}
 }
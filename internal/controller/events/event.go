@@ -6,9 +6,33 @@ package events
 type Event struct {
 	Reason string
 	Msg    string
+	// Warning records the event as Warning, failures are warnings
+	Warning bool
+	// Notify forwards the event to the notification sinks
+	Notify bool
+}
+
+// Failure returns a Warning event which is forwarded to the notification sinks
+func Failure(reason string, msg string) Event {
+	return Event{Reason: reason, Msg: msg, Warning: true, Notify: true}
 }
 
 const (
 	LocalStateChangeReason  string = "Local state change"
 	GlobalStateChangeReason string = "Global state change"
+	PlacementChangeReason   string = "Placement change"
+	RemediationReason       string = "Remediation"
+	FailuresResetReason     string = "Failures reset"
+)
+
+// Reasons of Warning events. They are stable codes to match failures on, failures of the
+// local operation use the reason of the Local condition.
+const (
+	DeploymentFailedReason     string = "DeploymentFailed"
+	DeploymentTimeoutReason    string = "DeploymentTimeout"
+	UndeploymentFailedReason   string = "UndeploymentFailed"
+	UndeploymentTimeoutReason  string = "UndeploymentTimeout"
+	RemediationFailedReason    string = "RemediationFailed"
+	RemediationExhaustedReason string = "RemediationExhausted"
+	GlobalFailureReason        string = "GlobalFailure"
 )
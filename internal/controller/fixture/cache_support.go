@@ -33,6 +33,10 @@ func NewTestClusterCacheWithOptions(opts []cache.UpdateSettingsFunc, objs ...run
 		GroupKind:            schema.GroupKind{Group: "", Kind: "Pod"},
 		GroupVersionResource: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"},
 		Meta:                 metav1.APIResource{Namespaced: true},
+	}, {
+		GroupKind:            schema.GroupKind{Group: "", Kind: "Service"},
+		GroupVersionResource: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"},
+		Meta:                 metav1.APIResource{Namespaced: true},
 	}, {
 		GroupKind:            schema.GroupKind{Group: "apps", Kind: "ReplicaSet"},
 		GroupVersionResource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"},
@@ -21,30 +21,40 @@ type globalApplication struct {
 	localApplications map[types.SpecificVersion]*local.LocalApplication
 	activeVersion     mo.Option[*types.SpecificVersion]
 	newVersion        mo.Option[*types.SpecificVersion]
-	application       *v1.AnyApplication
-	config            *config.ApplicationRuntimeConfig
-	clock             clock.Clock
-	log               logr.Logger
+	resolvedVersion   mo.Option[*types.SpecificVersion]
+	// unavailableVersion is set when the zone cannot fetch the version published by the owner
+	unavailableVersion mo.Option[*types.VersionUnavailableError]
+	application        *v1.AnyApplication
+	config             *config.ApplicationRuntimeConfig
+	peers              types.ZoneReachability
+	clock              clock.Clock
+	log                logr.Logger
 }
 
 func NewFromLocalApplication(
 	localApplications map[types.SpecificVersion]*local.LocalApplication,
 	activeVersion mo.Option[*types.SpecificVersion],
 	newVersion mo.Option[*types.SpecificVersion],
+	resolvedVersion mo.Option[*types.SpecificVersion],
+	unavailableVersion mo.Option[*types.VersionUnavailableError],
 	clock clock.Clock,
 	application *v1.AnyApplication,
 	config *config.ApplicationRuntimeConfig,
+	peers types.ZoneReachability,
 	log logr.Logger,
 ) types.GlobalApplication {
 	log = log.WithName("GlobalApplication")
 	return &globalApplication{
-		localApplications: localApplications,
-		application:       application,
-		activeVersion:     activeVersion,
-		newVersion:        newVersion,
-		config:            config,
-		clock:             clock,
-		log:               log,
+		localApplications:  localApplications,
+		application:        application,
+		activeVersion:      activeVersion,
+		newVersion:         newVersion,
+		resolvedVersion:    resolvedVersion,
+		unavailableVersion: unavailableVersion,
+		config:             config,
+		peers:              peers,
+		clock:              clock,
+		log:                log,
 	}
 }
 
@@ -122,24 +132,34 @@ func (g *globalApplication) DeriveNewStatus(
 	// Update local job conditions
 	stateUpdated = updateJobConditions(current, jobConditions, g.config.ZoneId) || stateUpdated
 
+	// Update availability of the published version
+	stateUpdated = updateTargetVersionCondition(current, g.unavailableVersion, g.clock, g.config) || stateUpdated
+
 	// Update state
 	newVersion, err := g.deriveTargetVersion()
 	if err != nil {
 		g.log.Error(err, "Failed to derive target version for global application", "application", g.application.Name)
+		status := mo.None[v1.AnyApplicationStatus]()
+		if stateUpdated {
+			status = mo.Some(*current)
+		}
 		return types.StatusResult{
-			Status: mo.None[v1.AnyApplicationStatus](),
+			Status: status,
 			Jobs:   types.NextJobs{},
 		}
 	}
 	globalStateUpdated, nextJobs := updateState(
 		g.application,
 		g.config,
+		g.peers,
+		g.clock,
 		jobFactory,
 		g.IsPresent(),
 		g.IsDeployed(),
 		g.NonActiveVersionsPresent(),
 		newVersion,
 		g.newVersion,
+		g.resolvedVersion,
 		runningJobType,
 	)
 
@@ -177,12 +197,15 @@ func (g *globalApplication) deriveTargetVersion() (*types.SpecificVersion, error
 func updateState(
 	applicationMut *v1.AnyApplication,
 	config *config.ApplicationRuntimeConfig,
+	peers types.ZoneReachability,
+	clock clock.Clock,
 	jobFactory types.AsyncJobFactory,
 	applicationPresent bool,
 	applicationDeployed bool,
 	nonActiveVersionsPresent bool,
 	version *types.SpecificVersion,
 	newVersion mo.Option[*types.SpecificVersion],
+	resolvedVersion mo.Option[*types.SpecificVersion],
 	runningJobType mo.Option[types.AsyncJobType],
 ) (bool, types.NextJobs) {
 	status := &applicationMut.Status
@@ -193,6 +216,7 @@ func updateState(
 		stateUpdated, nextJobs = localStateMachine(
 			applicationMut,
 			config,
+			clock,
 			jobFactory,
 			applicationPresent,
 			applicationDeployed,
@@ -207,8 +231,11 @@ func updateState(
 		globalStateUpdated, globalJobs := globalStateMachine(
 			applicationMut,
 			config,
+			peers,
+			clock,
 			jobFactory,
 			applicationDeployed,
+			resolvedVersion,
 			runningJobType,
 		)
 		stateUpdated = stateUpdated || globalStateUpdated
@@ -229,15 +256,24 @@ func updateState(
 func globalStateMachine(
 	application *v1.AnyApplication,
 	config *config.ApplicationRuntimeConfig,
+	peers types.ZoneReachability,
+	clock clock.Clock,
 	jobFactory types.AsyncJobFactory,
 	applicationResourcesAvailable bool,
+	resolvedVersion mo.Option[*types.SpecificVersion],
 	runningJobType mo.Option[types.AsyncJobType],
 ) (bool, types.NextJobs) {
 	status := &application.Status
 
 	stateUpdated := false
 
-	fsm := NewGlobalFSM(application, config, jobFactory, applicationResourcesAvailable, runningJobType)
+	// the placement zones deploy the version resolved by the owner
+	if version, present := resolvedVersion.Get(); present && status.Ownership.TargetVersion != version.ToString() {
+		status.Ownership.TargetVersion = version.ToString()
+		stateUpdated = true
+	}
+
+	fsm := NewGlobalFSM(application, config, peers, clock, jobFactory, applicationResourcesAvailable, resolvedVersion, runningJobType)
 	nextStateResult := fsm.NextState()
 
 	maybeNextState, conditionsToAdd, conditionsToRemove := nextStateResult.NextState, nextStateResult.ConditionsToAdd, nextStateResult.ConditionsToRemove
@@ -259,6 +295,17 @@ func globalStateMachine(
 		stateUpdated = true
 	})
 
+	if placements, present := nextStateResult.Placements.Get(); present {
+		status.Ownership.Placements = placements
+		status.Ownership.Epoch++
+		stateUpdated = true
+	}
+
+	if rollout, present := nextStateResult.Rollout.Get(); present {
+		status.Ownership.Rollout = rollout
+		stateUpdated = true
+	}
+
 	nextState := maybeNextState.OrElse(status.Ownership.State)
 	if status.Ownership.State != nextState {
 		status.Ownership.State = nextState
@@ -271,6 +318,7 @@ func globalStateMachine(
 func localStateMachine(
 	applicationMut *v1.AnyApplication,
 	config *config.ApplicationRuntimeConfig,
+	clock clock.Clock,
 	jobFactory types.AsyncJobFactory,
 	applicationResourcesPresent bool,
 	applicationDeployed bool,
@@ -285,6 +333,7 @@ func localStateMachine(
 	fsm := NewLocalFSM(
 		applicationMut,
 		config,
+		clock,
 		jobFactory,
 		applicationResourcesPresent,
 		applicationDeployed,
@@ -314,6 +363,11 @@ func localStateMachine(
 		stateUpdated = true
 	}
 
+	if resetAt, present := nextStateResult.ResetAcknowledged.Get(); present {
+		acknowledgeReset(&applicationMut.Status, resetAt, config.ZoneId)
+		stateUpdated = true
+	}
+
 	if jobs.JobsToAdd.IsPresent() || jobs.JobsToRemove.IsPresent() {
 		stateUpdated = true
 	}
@@ -321,6 +375,47 @@ func localStateMachine(
 	return stateUpdated, jobs
 }
 
+// updateTargetVersionCondition reports the placement zone which cannot fetch the version published by
+// the owner. The condition turns Available once the zone fetches the version again.
+func updateTargetVersionCondition(
+	status *v1.AnyApplicationStatus,
+	unavailableVersion mo.Option[*types.VersionUnavailableError],
+	clock clock.Clock,
+	config *config.ApplicationRuntimeConfig,
+) bool {
+	existing, found := findCondition(status, v1.TargetVersionConditionType, config.ZoneId)
+	condition := &v1.ConditionStatus{
+		Type:               v1.TargetVersionConditionType,
+		ZoneId:             config.ZoneId,
+		LastTransitionTime: clock.NowTime(),
+	}
+	if unavailable, isUnavailable := unavailableVersion.Get(); isUnavailable {
+		if !placementsContainZone(status, config.ZoneId) ||
+			found && existing.Status == string(v1.TargetVersionStatusUnavailable) && existing.Msg == unavailable.Error() {
+			return false
+		}
+		condition.Status = string(v1.TargetVersionStatusUnavailable)
+		condition.Reason = "VersionUnavailable"
+		condition.Msg = unavailable.Error()
+	} else {
+		if !found || existing.Status != string(v1.TargetVersionStatusUnavailable) {
+			return false
+		}
+		condition.Status = string(v1.TargetVersionStatusAvailable)
+		condition.Msg = "Chart version " + status.Ownership.TargetVersion + " is available"
+	}
+	addOrUpdateCondition(status, condition, config.ZoneId)
+	return true
+}
+
+func findCondition(status *v1.AnyApplicationStatus, conditionType v1.ApplicationConditionType, zoneId string) (*v1.ConditionStatus, bool) {
+	zoneStatus, found := status.GetStatusFor(zoneId)
+	if !found {
+		return nil, false
+	}
+	return getCondition(zoneStatus.Conditions, conditionType, zoneId)
+}
+
 func updateJobConditions(status *v1.AnyApplicationStatus, jobConditions types.JobApplicationCondition, zoneId string) bool {
 	stateUpdated := false
 
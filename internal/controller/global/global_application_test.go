@@ -15,6 +15,7 @@ import (
 	"hiro.io/anyapplication/internal/controller/job"
 	"hiro.io/anyapplication/internal/controller/local"
 	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/peers"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -39,7 +40,7 @@ var _ = Describe("GlobalApplication", func() {
 			applicationResource.Status.Ownership.Owner = ""
 			localApplication := make(map[types.SpecificVersion]*local.LocalApplication)
 			globalApplication := NewFromLocalApplication(localApplication,
-				mo.Some(version), mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log,
+				mo.Some(version), mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log,
 			)
 			jobFactory := job.NewAsyncJobFactory(runtimeConfig, fakeClock, logf.Log, &events)
 
@@ -91,7 +92,7 @@ var _ = Describe("GlobalApplication", func() {
 
 			localApplications := make(map[types.SpecificVersion]*local.LocalApplication)
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version), mo.None[*types.SpecificVersion](),
-				fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 			existingJobCondition := types.EmptyJobConditions()
 
 			Expect(globalApplication.IsDeployed()).To(BeFalse())
@@ -158,7 +159,7 @@ var _ = Describe("GlobalApplication", func() {
 
 			localApplications := make(map[types.SpecificVersion]*local.LocalApplication)
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version), mo.None[*types.SpecificVersion](),
-				fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeFalse())
 			Expect(globalApplication.IsPresent()).To(BeFalse())
@@ -246,7 +247,7 @@ var _ = Describe("GlobalApplication", func() {
 			localApp := local.FakeLocalApplication(runtimeConfig, version, fakeClock, true)
 			localApplications := map[types.SpecificVersion]*local.LocalApplication{*version: &localApp}
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeTrue())
 			Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -329,7 +330,7 @@ var _ = Describe("GlobalApplication", func() {
 
 			localApplications := make(map[types.SpecificVersion]*local.LocalApplication)
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeFalse())
 			Expect(globalApplication.IsPresent()).To(BeFalse())
@@ -425,7 +426,7 @@ var _ = Describe("GlobalApplication", func() {
 				*version: &localApp,
 			}
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeTrue())
 			Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -509,7 +510,7 @@ var _ = Describe("GlobalApplication", func() {
 
 			localApplications := make(map[types.SpecificVersion]*local.LocalApplication)
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeFalse())
 			Expect(globalApplication.IsPresent()).To(BeFalse())
@@ -578,7 +579,7 @@ var _ = Describe("GlobalApplication", func() {
 			localApp := local.FakeLocalApplication(runtimeConfig, version, fakeClock, true)
 			localApplications := map[types.SpecificVersion]*local.LocalApplication{*version: &localApp}
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeTrue())
 			Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -655,7 +656,7 @@ var _ = Describe("GlobalApplication", func() {
 
 			localApplications := map[types.SpecificVersion]*local.LocalApplication{*version: &fakeLocalApp}
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeFalse())
 			Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -731,7 +732,7 @@ var _ = Describe("GlobalApplication", func() {
 			localApp := local.FakeLocalApplication(runtimeConfig, version, fakeClock, true)
 			localApplications := map[types.SpecificVersion]*local.LocalApplication{*version: &localApp}
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeTrue())
 			Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -801,7 +802,7 @@ var _ = Describe("GlobalApplication", func() {
 
 			localApplications := make(map[types.SpecificVersion]*local.LocalApplication)
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeFalse())
 			Expect(globalApplication.IsPresent()).To(BeFalse())
@@ -868,7 +869,7 @@ var _ = Describe("GlobalApplication", func() {
 
 			localApplications := make(map[types.SpecificVersion]*local.LocalApplication)
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.None[*types.SpecificVersion](), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeFalse())
 			Expect(globalApplication.IsPresent()).To(BeFalse())
@@ -941,7 +942,7 @@ var _ = Describe("GlobalApplication", func() {
 
 			localApplications := make(map[types.SpecificVersion]*local.LocalApplication)
 			globalApplication := NewFromLocalApplication(localApplications, mo.None[*types.SpecificVersion](),
-				mo.Some(newVersion), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.Some(newVersion), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeFalse())
 			Expect(globalApplication.IsPresent()).To(BeFalse())
@@ -1002,6 +1003,7 @@ var _ = Describe("GlobalApplication", func() {
 			applicationResource := makeApplication()
 			applicationResource.Status.Ownership.State = v1.OperationalGlobalState
 			applicationResource.Status.Ownership.Placements = []v1.Placement{{Zone: currentZone}}
+			applicationResource.Spec.UpgradeStrategy.Type = v1.UpgradeStrategyRecreate
 
 			applicationResource.Status.Zones = []v1.ZoneStatus{
 				{
@@ -1023,7 +1025,7 @@ var _ = Describe("GlobalApplication", func() {
 			localApp := local.FakeLocalApplication(runtimeConfig, version, fakeClock, true)
 			localApplications := map[types.SpecificVersion]*local.LocalApplication{*version: &localApp}
 			globalApplication := NewFromLocalApplication(localApplications, mo.Some(version),
-				mo.Some(newVersion), fakeClock, applicationResource, runtimeConfig, logf.Log)
+				mo.Some(newVersion), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, applicationResource, runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 			Expect(globalApplication.IsDeployed()).To(BeTrue())
 			Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -4,31 +4,45 @@
 package global
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/argoproj/gitops-engine/pkg/health"
+	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/samber/lo"
 	"github.com/samber/mo"
 	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
 	"hiro.io/anyapplication/internal/config"
 	"hiro.io/anyapplication/internal/controller/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type GlobalFSM struct {
 	application        *v1.AnyApplication
 	config             *config.ApplicationRuntimeConfig
+	peers              types.ZoneReachability
+	clock              clock.Clock
 	jobFactory         types.AsyncJobFactory
 	applicationPresent bool
+	resolvedVersion    mo.Option[*types.SpecificVersion]
 	runningJobType     mo.Option[types.AsyncJobType]
 }
 
 func NewGlobalFSM(
 	application *v1.AnyApplication,
 	config *config.ApplicationRuntimeConfig,
+	peers types.ZoneReachability,
+	clock clock.Clock,
 	jobFactory types.AsyncJobFactory,
 	applicationPresent bool,
+	resolvedVersion mo.Option[*types.SpecificVersion],
 	runningJobType mo.Option[types.AsyncJobType],
 ) GlobalFSM {
 	return GlobalFSM{
-		application, config, jobFactory, applicationPresent, runningJobType,
+		application, config, peers, clock, jobFactory, applicationPresent, resolvedVersion, runningJobType,
 	}
 }
 
@@ -42,7 +56,25 @@ func (g *GlobalFSM) NextState() types.NextStateResult {
 	if !placementExists(status) {
 		return g.handlePlacementState()
 	}
-	if isFailureCondition(g.application) {
+	if isRelocating(status) {
+		return g.handleRelocationProgress()
+	}
+	if staleZones := g.staleZones(); len(staleZones) > 0 && !g.isRelocationBackingOff() {
+		if result, updated := g.handleRelocation(staleZones, "ZoneStale", "stale zones"); updated {
+			return result
+		}
+	} else if requesting := g.relocationRequests(); len(requesting) > 0 && !g.isRelocationBackingOff() {
+		if result, updated := g.handleRelocation(requesting, "RelocationRequested", "zones requesting relocation"); updated {
+			return result
+		}
+	}
+	if result, scaled := g.handleScaling(); scaled {
+		return result
+	}
+	if result, updated := g.handleRollout(); updated {
+		return result
+	}
+	if isFailureCondition(g.application, g.peers) {
 		return g.handleFailureState()
 	}
 	state := getGlobalState(&g.application.Status)
@@ -84,12 +116,496 @@ func (g *GlobalFSM) handlePlacementState() types.NextStateResult {
 			}
 		}
 	}
+	if spec.PlacementStrategy.Strategy == v1.PlacementStrategyGlobal {
+		return g.handleGlobalPlacement()
+	}
 
 	return types.NextStateResult{
 		NextState: mo.Some(v1.PlacementGlobalState),
 	}
 }
 
+// handleGlobalPlacement places the application in the best scored reachable zones
+func (g *GlobalFSM) handleGlobalPlacement() types.NextStateResult {
+	scorer := NewPlacementScorer(&g.application.Spec.PlacementStrategy)
+	scores := rankZones(scorer.Score(g.application, g.peers.Zones()))
+
+	count := max(g.application.Spec.Zones, 1)
+	placements := make([]v1.Placement, 0, count)
+	for _, score := range scores {
+		if len(placements) == count {
+			break
+		}
+		placements = append(placements, v1.Placement{Zone: score.ZoneId})
+	}
+
+	condition := &v1.ConditionStatus{
+		Type:               v1.PlacementConditionType,
+		ZoneId:             g.config.ZoneId,
+		LastTransitionTime: g.clock.NowTime(),
+	}
+	if len(placements) == 0 {
+		condition.Status = string(v1.PlacementStatusFailure)
+		condition.Reason = "NoZoneAvailable"
+		condition.Msg = fmt.Sprintf("Scorer %s found no zone for the application", scorer.Name())
+		return types.NextStateResult{
+			NextState:       mo.Some(v1.FailureGlobalState),
+			ConditionsToAdd: mo.Some(condition),
+		}
+	}
+	zones := lo.Map(placements, func(placement v1.Placement, _ int) string { return placement.Zone })
+	condition.Status = string(v1.PlacementStatusDone)
+	condition.Msg = fmt.Sprintf("Scorer %s placed the application in %s. Scores: %s",
+		scorer.Name(), strings.Join(zones, ", "), formatScores(scores))
+	return types.NextStateResult{
+		NextState:       mo.Some(v1.PlacementGlobalState),
+		ConditionsToAdd: mo.Some(condition),
+		Placements:      mo.Some(placements),
+	}
+}
+
+// handleRelocation moves the stale placement zones, or those which request relocation, to reachable
+// zones which do not host the application yet. They keep their placement until the new zones report healthy.
+// Without such zones the failure is recorded once, it tells whether the status changed.
+func (g *GlobalFSM) handleRelocation(staleZones []string, reason string, subject string) (types.NextStateResult, bool) {
+	scorer := NewPlacementScorer(&g.application.Spec.PlacementStrategy)
+	scores := rankZones(scorer.Score(g.application, g.relocationCandidates()))
+
+	candidates := lo.Map(scores, func(score ZoneScore, _ int) string { return score.ZoneId })
+	incoming := make([]v1.Placement, 0, len(staleZones))
+	relocated := make([]string, 0, len(staleZones))
+	for _, staleZone := range staleZones {
+		if len(candidates) == 0 {
+			break
+		}
+		incoming = append(incoming, v1.Placement{Zone: candidates[0], Replaces: staleZone})
+		relocated = append(relocated, staleZone+" to "+candidates[0])
+		candidates = candidates[1:]
+	}
+
+	if len(incoming) == 0 {
+		msg := "No zone available to replace " + subject + " " + strings.Join(staleZones, ", ")
+		if existing, found := g.ownCondition(v1.PlacementConditionType); found &&
+			existing.Status == string(v1.PlacementStatusFailure) && existing.Reason == reason && existing.Msg == msg {
+			return types.NextStateResult{}, false
+		}
+		return types.NextStateResult{
+			ConditionsToAdd: mo.Some(&v1.ConditionStatus{
+				Type:               v1.PlacementConditionType,
+				ZoneId:             g.config.ZoneId,
+				Status:             string(v1.PlacementStatusFailure),
+				LastTransitionTime: g.clock.NowTime(),
+				Reason:             reason,
+				Msg:                msg,
+			}),
+		}, true
+	}
+	msg := fmt.Sprintf("Relocating %s: %s. Scorer %s, scores: %s",
+		subject, strings.Join(relocated, ", "), scorer.Name(), formatScores(scores))
+	return g.startRelocation(incoming, reason, msg), true
+}
+
+// handleScaling reconciles the number of placements with the number of zones in the spec.
+// It tells whether the placements changed.
+func (g *GlobalFSM) handleScaling() (types.NextStateResult, bool) {
+	placements := g.application.Status.Ownership.Placements
+	desired := max(g.application.Spec.Zones, 1)
+	switch {
+	case len(placements) == 0:
+		// nothing was placed, scaling starts from the initial placement
+	case len(placements) < desired:
+		return g.scaleUp(desired)
+	case len(placements) > desired:
+		return g.scaleDown(desired), true
+	}
+	return types.NextStateResult{}, false
+}
+
+// scaleUp places the application in the best scored zones which do not host it yet. Without
+// such zones the failure is recorded once and the application keeps running in its zones.
+func (g *GlobalFSM) scaleUp(desired int) (types.NextStateResult, bool) {
+	placements := g.application.Status.Ownership.Placements
+	scorer := NewPlacementScorer(&g.application.Spec.PlacementStrategy)
+	scores := rankZones(scorer.Score(g.application, g.relocationCandidates()))
+
+	added := make([]string, 0, desired-len(placements))
+	for _, score := range scores {
+		if len(placements)+len(added) == desired {
+			break
+		}
+		added = append(added, score.ZoneId)
+	}
+
+	condition := &v1.ConditionStatus{
+		Type:               v1.PlacementConditionType,
+		ZoneId:             g.config.ZoneId,
+		LastTransitionTime: g.clock.NowTime(),
+	}
+	if len(added) == 0 {
+		if existing, found := g.ownCondition(v1.PlacementConditionType); found &&
+			existing.Status == string(v1.PlacementStatusFailure) && existing.Reason == "ScaleUpFailed" {
+			return types.NextStateResult{}, false
+		}
+		condition.Status = string(v1.PlacementStatusFailure)
+		condition.Reason = "ScaleUpFailed"
+		condition.Msg = fmt.Sprintf("Scorer %s found no zone to scale from %d to %d zones", scorer.Name(), len(placements), desired)
+		return types.NextStateResult{ConditionsToAdd: mo.Some(condition)}, true
+	}
+
+	next := slices.Clone(placements)
+	for _, zone := range added {
+		next = append(next, v1.Placement{Zone: zone})
+	}
+	condition.Status = string(v1.PlacementStatusDone)
+	condition.Reason = "ScaledUp"
+	condition.Msg = fmt.Sprintf("Scaled from %d to %d zones, added %s. Scorer %s, scores: %s",
+		len(placements), len(next), strings.Join(added, ", "), scorer.Name(), formatScores(scores))
+	return types.NextStateResult{
+		NextState:       mo.Some(v1.RelocationGlobalState),
+		ConditionsToAdd: mo.Some(condition),
+		Placements:      mo.Some(next),
+	}, true
+}
+
+// scaleDown removes the surplus placements. Unreachable and unhealthy zones are removed first,
+// then the most recently added ones. The owner zone is removed last.
+func (g *GlobalFSM) scaleDown(desired int) types.NextStateResult {
+	placements := g.application.Status.Ownership.Placements
+	priority := func(placement v1.Placement) int {
+		switch {
+		case placement.Zone == g.config.ZoneId:
+			return 2
+		case g.peers.IsUnreachable(placement.Zone) || !g.isHealthy(placement.Zone):
+			return 0
+		}
+		return 1
+	}
+	candidates := slices.Clone(placements)
+	slices.Reverse(candidates)
+	slices.SortStableFunc(candidates, func(a, b v1.Placement) int { return priority(a) - priority(b) })
+
+	removed := lo.Map(candidates[:len(placements)-desired], func(placement v1.Placement, _ int) string { return placement.Zone })
+	next := lo.Filter(placements, func(placement v1.Placement, _ int) bool {
+		return !lo.Contains(removed, placement.Zone)
+	})
+	return types.NextStateResult{
+		NextState: mo.Some(v1.RelocationGlobalState),
+		ConditionsToAdd: mo.Some(&v1.ConditionStatus{
+			Type:               v1.PlacementConditionType,
+			ZoneId:             g.config.ZoneId,
+			Status:             string(v1.PlacementStatusDone),
+			LastTransitionTime: g.clock.NowTime(),
+			Reason:             "ScaledDown",
+			Msg:                fmt.Sprintf("Scaled from %d to %d zones, removed %s", len(placements), len(next), strings.Join(removed, ", ")),
+		}),
+		Placements: mo.Some(next),
+	}
+}
+
+// startRelocation places the application in the incoming zones next to the zones they replace
+func (g *GlobalFSM) startRelocation(incoming []v1.Placement, reason string, msg string) types.NextStateResult {
+	placements := append(slices.Clone(g.application.Status.Ownership.Placements), incoming...)
+	return types.NextStateResult{
+		NextState: mo.Some(v1.RelocationGlobalState),
+		ConditionsToAdd: mo.Some(&v1.ConditionStatus{
+			Type:               v1.RelocationConditionType,
+			ZoneId:             g.config.ZoneId,
+			Status:             string(v1.RelocationStatusInProgress),
+			LastTransitionTime: g.clock.NowTime(),
+			Reason:             reason,
+			Msg:                msg,
+		}),
+		Placements: mo.Some(placements),
+	}
+}
+
+// handleRelocationProgress releases a replaced zone once the zone replacing it reports healthy.
+// Zones which do not become healthy within the relocation timeout are dropped again and the
+// replaced zones keep the application.
+func (g *GlobalFSM) handleRelocationProgress() types.NextStateResult {
+	status := &g.application.Status
+	now := g.clock.NowTime()
+
+	condition, found := g.ownCondition(v1.RelocationConditionType)
+	if !found || condition.Status != string(v1.RelocationStatusInProgress) {
+		// the start of the relocation was not recorded, the timeout starts now
+		return types.NextStateResult{
+			NextState: mo.Some(v1.RelocationGlobalState),
+			ConditionsToAdd: mo.Some(&v1.ConditionStatus{
+				Type:               v1.RelocationConditionType,
+				ZoneId:             g.config.ZoneId,
+				Status:             string(v1.RelocationStatusInProgress),
+				LastTransitionTime: now,
+				Msg:                "Relocating to " + strings.Join(incomingZones(status), ", "),
+			}),
+		}
+	}
+	timedOut := g.config.RelocationTimeout > 0 && now.Sub(condition.LastTransitionTime.Time) > g.config.RelocationTimeout
+
+	released := mapset.NewSet[string]()
+	completed := make([]string, 0)
+	rolledBack := make([]string, 0)
+	pending := 0
+	for _, placement := range status.Ownership.Placements {
+		switch {
+		case placement.Replaces == "":
+		case g.isHealthy(placement.Zone):
+			released.Add(placement.Replaces)
+			completed = append(completed, placement.Replaces+" to "+placement.Zone)
+		case timedOut:
+			rolledBack = append(rolledBack, placement.Zone)
+		default:
+			pending++
+		}
+	}
+	if len(completed) == 0 && len(rolledBack) == 0 {
+		return types.NextStateResult{
+			NextState: mo.Some(v1.RelocationGlobalState),
+		}
+	}
+
+	placements := make([]v1.Placement, 0, len(status.Ownership.Placements))
+	for _, placement := range status.Ownership.Placements {
+		if placement.Replaces == "" && released.Contains(placement.Zone) {
+			continue
+		}
+		if placement.Replaces != "" && lo.Contains(rolledBack, placement.Zone) {
+			continue
+		}
+		if placement.Replaces != "" && released.Contains(placement.Replaces) {
+			placement.Replaces = ""
+		}
+		placements = append(placements, placement)
+	}
+
+	result := types.NextStateResult{
+		NextState:  mo.Some(v1.RelocationGlobalState),
+		Placements: mo.Some(placements),
+	}
+	if pending > 0 {
+		return result
+	}
+	finished := &v1.ConditionStatus{
+		Type:               v1.RelocationConditionType,
+		ZoneId:             g.config.ZoneId,
+		Status:             string(v1.RelocationStatusDone),
+		LastTransitionTime: now,
+		Reason:             condition.Reason,
+		Msg:                "Relocated " + strings.Join(completed, ", "),
+	}
+	if len(rolledBack) > 0 {
+		finished.Status = string(v1.RelocationStatusRolledBack)
+		finished.Reason = "RelocationTimeout"
+		finished.Msg = fmt.Sprintf("Zones %s did not report healthy within %s, relocation rolled back",
+			strings.Join(rolledBack, ", "), g.config.RelocationTimeout)
+		if len(completed) > 0 {
+			finished.Msg += ". Relocated " + strings.Join(completed, ", ")
+		}
+	}
+	result.ConditionsToAdd = mo.Some(finished)
+	return result
+}
+
+// handleRollout records the chart version resolved by the owner and admits the placement zones
+// to it wave by wave: the canary zone first, then batches of the remaining zones. A wave starts
+// once all zones of the previous waves report the version healthy, a failing zone halts the rollout.
+// It tells whether the rollout status changed.
+func (g *GlobalFSM) handleRollout() (types.NextStateResult, bool) {
+	resolvedVersion, found := g.resolvedVersion.Get()
+	if !found {
+		return types.NextStateResult{}, false
+	}
+	version := resolvedVersion.ToString()
+	rollout := g.application.Status.Ownership.Rollout
+	switch {
+	case rollout == nil:
+		return g.startRollout(g.deployedVersion(version), version), true
+	case rollout.Version != version:
+		previousVersion := rollout.PreviousVersion
+		if rollout.Phase == v1.RolloutPhaseCompleted || previousVersion == "" {
+			previousVersion = rollout.Version
+		}
+		return g.startRollout(previousVersion, version), true
+	case rollout.Phase == v1.RolloutPhaseProgressing:
+		return g.handleRolloutProgress(rollout)
+	}
+	return types.NextStateResult{}, false
+}
+
+// startRollout admits the canary zone to the version. Without a previous version
+// nothing is deployed yet and all zones install the version at once.
+func (g *GlobalFSM) startRollout(previousVersion string, version string) types.NextStateResult {
+	zones := placementZones(&g.application.Status)
+	if previousVersion == "" || len(zones) == 0 {
+		return types.NextStateResult{
+			Rollout: mo.Some(&v1.RolloutStatus{
+				Version: version,
+				Phase:   v1.RolloutPhaseCompleted,
+				Zones:   zones,
+				Message: "Installing version " + version,
+			}),
+		}
+	}
+	canary := g.application.Spec.UpgradeStrategy.GetRollout().CanaryZone
+	if !lo.Contains(zones, canary) {
+		canary = zones[0]
+	}
+	return types.NextStateResult{
+		Rollout: mo.Some(&v1.RolloutStatus{
+			Version:         version,
+			PreviousVersion: previousVersion,
+			Phase:           v1.RolloutPhaseProgressing,
+			Wave:            1,
+			Zones:           []string{canary},
+			Message:         fmt.Sprintf("Rolling out version %s to canary zone %s", version, canary),
+		}),
+	}
+}
+
+func (g *GlobalFSM) handleRolloutProgress(rollout *v1.RolloutStatus) (types.NextStateResult, bool) {
+	zones := placementZones(&g.application.Status)
+	next := rollout.DeepCopy()
+	for _, zone := range rollout.Zones {
+		if !lo.Contains(zones, zone) {
+			continue
+		}
+		if reason, failed := g.rolloutFailure(zone, rollout.Version); failed {
+			next.Phase = v1.RolloutPhaseHalted
+			next.Message = fmt.Sprintf("Zone %s failed on version %s, rollout halted: %s", zone, rollout.Version, reason)
+			return types.NextStateResult{Rollout: mo.Some(next)}, true
+		}
+		if !g.isRolledOut(zone, rollout.Version) {
+			return types.NextStateResult{}, false
+		}
+	}
+
+	remaining := lo.Filter(zones, func(zone string, _ int) bool { return !lo.Contains(rollout.Zones, zone) })
+	if len(remaining) == 0 {
+		next.Phase = v1.RolloutPhaseCompleted
+		next.Message = "Rolled out version " + rollout.Version + " to all zones"
+		return types.NextStateResult{Rollout: mo.Some(next)}, true
+	}
+	batch := remaining[:min(g.application.Spec.UpgradeStrategy.GetRollout().BatchSize, len(remaining))]
+	next.Wave++
+	next.Zones = append(next.Zones, batch...)
+	next.Message = fmt.Sprintf("Rolling out version %s to zones %s, wave %d", rollout.Version, strings.Join(batch, ", "), next.Wave)
+	return types.NextStateResult{Rollout: mo.Some(next)}, true
+}
+
+// isRolledOut tells whether the zone reports the version healthy
+func (g *GlobalFSM) isRolledOut(zoneId string, version string) bool {
+	zoneStatus, found := g.application.Status.GetStatusFor(zoneId)
+	return found && zoneStatus.ChartVersion == version && g.isHealthy(zoneId)
+}
+
+// rolloutFailure returns the reason why the zone failed to deploy the version
+func (g *GlobalFSM) rolloutFailure(zoneId string, version string) (string, bool) {
+	zoneStatus, found := g.application.Status.GetStatusFor(zoneId)
+	if !found || zoneStatus.ChartVersion != version {
+		return "", false
+	}
+	if condition, found := getCondition(zoneStatus.Conditions, v1.DeploymentConditionType, zoneId); found &&
+		condition.Status == string(v1.DeploymentStatusFailure) {
+		return condition.Msg, true
+	}
+	if condition, found := getCondition(zoneStatus.Conditions, v1.LocalConditionType, zoneId); found &&
+		(condition.Status == string(health.HealthStatusDegraded) || condition.Status == string(health.HealthStatusMissing)) {
+		return "application is " + condition.Status, true
+	}
+	return "", false
+}
+
+// deployedVersion returns a version other than the given one which the placement zones report.
+// Applications deployed before rollouts were recorded are upgraded progressively from it.
+func (g *GlobalFSM) deployedVersion(version string) string {
+	for _, zone := range placementZones(&g.application.Status) {
+		zoneStatus, found := g.application.Status.GetStatusFor(zone)
+		if found && zoneStatus.ChartVersion != "" && zoneStatus.ChartVersion != version {
+			return zoneStatus.ChartVersion
+		}
+	}
+	return ""
+}
+
+// isRelocationBackingOff delays the next relocation by the relocation timeout after a rollback
+func (g *GlobalFSM) isRelocationBackingOff() bool {
+	if g.config.RelocationTimeout <= 0 {
+		return false
+	}
+	condition, found := g.ownCondition(v1.RelocationConditionType)
+	return found && condition.Status == string(v1.RelocationStatusRolledBack) &&
+		g.clock.NowTime().Sub(condition.LastTransitionTime.Time) <= g.config.RelocationTimeout
+}
+
+func (g *GlobalFSM) ownCondition(conditionType v1.ApplicationConditionType) (*v1.ConditionStatus, bool) {
+	zoneStatus, found := g.application.Status.GetStatusFor(g.config.ZoneId)
+	if !found {
+		return nil, false
+	}
+	return zoneStatus.FindCondition(conditionType)
+}
+
+// isHealthy tells whether the zone reports the application healthy
+func (g *GlobalFSM) isHealthy(zoneId string) bool {
+	zoneStatus, found := g.application.Status.GetStatusFor(zoneId)
+	if !found {
+		return false
+	}
+	condition, found := getCondition(zoneStatus.Conditions, v1.LocalConditionType, zoneId)
+	return found && condition.Status == string(health.HealthStatusHealthy)
+}
+
+// staleZones returns the placement zones whose heartbeat is older than the stale threshold.
+// Zones which never reported a heartbeat are not stale.
+func (g *GlobalFSM) staleZones() []string {
+	staleZones := make([]string, 0)
+	for _, placement := range g.application.Status.Ownership.Placements {
+		if g.isStale(placement.Zone) {
+			staleZones = append(staleZones, placement.Zone)
+		}
+	}
+	return staleZones
+}
+
+// relocationRequests returns the placement zones which request relocation as a recover action
+func (g *GlobalFSM) relocationRequests() []string {
+	requesting := make([]string, 0)
+	for _, placement := range g.application.Status.Ownership.Placements {
+		zoneStatus, found := g.application.Status.GetStatusFor(placement.Zone)
+		if !found {
+			continue
+		}
+		condition, found := getCondition(zoneStatus.Conditions, v1.RemediationConditionType, placement.Zone)
+		if found && condition.Reason == string(v1.RecoverActionRelocate) &&
+			condition.Status != string(v1.RemediationStatusRecovered) {
+			requesting = append(requesting, placement.Zone)
+		}
+	}
+	return requesting
+}
+
+func (g *GlobalFSM) isStale(zoneId string) bool {
+	if g.config.ZoneStaleThreshold <= 0 || zoneId == g.config.ZoneId {
+		return false
+	}
+	zoneStatus, found := g.application.Status.GetStatusFor(zoneId)
+	if !found || zoneStatus.LastHeartbeatTime == nil {
+		return false
+	}
+	return g.clock.NowTime().Sub(zoneStatus.LastHeartbeatTime.Time) > g.config.ZoneStaleThreshold
+}
+
+// relocationCandidates returns the reachable zones which do not host the application and are not stale
+func (g *GlobalFSM) relocationCandidates() []types.ZoneInfo {
+	placed := mapset.NewSet[string]()
+	for _, placement := range g.application.Status.Ownership.Placements {
+		placed.Add(placement.Zone)
+	}
+	return lo.Filter(g.peers.Zones(), func(zone types.ZoneInfo, _ int) bool {
+		return !placed.Contains(zone.ZoneId) && !g.isStale(zone.ZoneId) && !g.peers.IsUnreachable(zone.ZoneId)
+	})
+}
+
 func (g *GlobalFSM) handleFailureState() types.NextStateResult {
 	return types.NextStateResult{
 		NextState: mo.Some(v1.FailureGlobalState),
@@ -140,13 +656,13 @@ func getCondition(conditions []v1.ConditionStatus, conditionType v1.ApplicationC
 
 func addOrUpdateCondition(status *v1.AnyApplicationStatus, condition *v1.ConditionStatus, zoneId string) {
 	zoneStatus := status.GetOrCreateStatusFor(zoneId)
-	existing, ok := lo.Find(zoneStatus.Conditions, func(existing v1.ConditionStatus) bool {
+	_, index, ok := lo.FindIndexOf(zoneStatus.Conditions, func(existing v1.ConditionStatus) bool {
 		return existing.Type == condition.Type && existing.ZoneId == condition.ZoneId
 	})
 	if !ok {
 		zoneStatus.Conditions = append(zoneStatus.Conditions, *condition)
 	} else {
-		condition.DeepCopyInto(&existing)
+		condition.DeepCopyInto(&zoneStatus.Conditions[index])
 	}
 }
 
@@ -166,12 +682,23 @@ func setNewVersion(status *v1.AnyApplicationStatus, newVersion *types.SpecificVe
 	zoneStatus.ChartVersion = newVersion.ToString()
 }
 
-func isFailureCondition(application *v1.AnyApplication) bool {
+func acknowledgeReset(status *v1.AnyApplicationStatus, resetAt time.Time, zoneId string) {
+	zoneStatus := status.GetOrCreateStatusFor(zoneId)
+	lastResetTime := metav1.NewTime(resetAt)
+	zoneStatus.LastResetTime = &lastResetTime
+}
+
+// isFailureCondition counts the zones reporting failures. Zones which do not respond to pings
+// are not counted, they may still run the application and are left to the stale zone relocation.
+func isFailureCondition(application *v1.AnyApplication, peers types.ZoneReachability) bool {
 	status := &application.Status
 	spec := &application.Spec
 
-	failedConditionCount := 0
+	failedZones := mapset.NewSet[string]()
 	for _, zoneStatus := range status.Zones {
+		if peers.IsUnreachable(zoneStatus.ZoneId) {
+			continue
+		}
 		zoneFailedConditions := false
 		for _, condition := range zoneStatus.Conditions {
 			switch condition.Type {
@@ -190,10 +717,28 @@ func isFailureCondition(application *v1.AnyApplication) bool {
 			}
 		}
 		if zoneFailedConditions {
-			failedConditionCount++
+			failedZones.Add(zoneStatus.ZoneId)
 		}
 	}
-	return failedConditionCount > spec.RecoverStrategy.Tolerance
+	return failedZones.Cardinality() > spec.RecoverStrategy.Tolerance
+}
+
+// isRelocating tells whether some placement still waits to take over from another zone
+func isRelocating(status *v1.AnyApplicationStatus) bool {
+	return lo.ContainsBy(status.Ownership.Placements, func(placement v1.Placement) bool {
+		return placement.Replaces != ""
+	})
+}
+
+func incomingZones(status *v1.AnyApplicationStatus) []string {
+	incoming := lo.Filter(status.Ownership.Placements, func(placement v1.Placement, _ int) bool {
+		return placement.Replaces != ""
+	})
+	return lo.Map(incoming, func(placement v1.Placement, _ int) string { return placement.Zone })
+}
+
+func placementZones(status *v1.AnyApplicationStatus) []string {
+	return lo.Map(status.Ownership.Placements, func(placement v1.Placement, _ int) string { return placement.Zone })
 }
 
 func placementExists(status *v1.AnyApplicationStatus) bool {
@@ -0,0 +1,718 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package global
+
+import (
+	"errors"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/mo"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
+	"hiro.io/anyapplication/internal/config"
+	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/peers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("isFailureCondition", func() {
+	var (
+		application  *v1.AnyApplication
+		reachability *peers.FakeReachability
+	)
+
+	BeforeEach(func() {
+		reachability = peers.NewFakeReachability()
+		reachability.AddZones("zone-a", "zone-b")
+		application = makeApplication()
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone-a"}, {Zone: "zone-b"}}
+		application.Status.Zones = []v1.ZoneStatus{
+			{
+				ZoneId: "zone-a",
+				Conditions: []v1.ConditionStatus{
+					{Type: v1.LocalConditionType, ZoneId: "zone-a", Status: string(health.HealthStatusHealthy)},
+				},
+			},
+			{
+				ZoneId: "zone-b",
+				Conditions: []v1.ConditionStatus{
+					{Type: v1.LocalConditionType, ZoneId: "zone-b", Status: string(health.HealthStatusHealthy)},
+				},
+			},
+		}
+	})
+
+	It("should not fail when all zones are healthy", func() {
+		Expect(isFailureCondition(application, reachability)).To(BeFalse())
+	})
+
+	It("should count zones reporting failures", func() {
+		application.Status.Zones[1].Conditions[0].Status = string(health.HealthStatusDegraded)
+		Expect(isFailureCondition(application, reachability)).To(BeTrue())
+	})
+
+	It("should tolerate failed zones up to the tolerance", func() {
+		application.Spec.RecoverStrategy.Tolerance = 1
+		application.Status.Zones[1].Conditions[0].Status = string(health.HealthStatusDegraded)
+		Expect(isFailureCondition(application, reachability)).To(BeFalse())
+	})
+
+	It("should not count unreachable zones whose last reported status is failed", func() {
+		application.Status.Zones[1].Conditions = []v1.ConditionStatus{
+			{Type: v1.DeploymentConditionType, ZoneId: "zone-b", Status: string(v1.DeploymentStatusFailure)},
+		}
+		reachability.SetUnreachable("zone-b", true)
+		Expect(isFailureCondition(application, reachability)).To(BeFalse())
+
+		reachability.SetUnreachable("zone-b", false)
+		Expect(isFailureCondition(application, reachability)).To(BeTrue())
+	})
+})
+
+var _ = Describe("GlobalFSM relocation", func() {
+	var (
+		application   *v1.AnyApplication
+		runtimeConfig *config.ApplicationRuntimeConfig
+		fakeClock     *clock.FakeClock
+		reachability  *peers.FakeReachability
+	)
+
+	heartbeatAgo := func(duration time.Duration) *metav1.Time {
+		heartbeat := metav1.NewTime(fakeClock.NowTime().Add(-duration))
+		return &heartbeat
+	}
+
+	nextState := func() types.NextStateResult {
+		fsm := NewGlobalFSM(application, runtimeConfig, reachability, fakeClock, nil, true, mo.None[*types.SpecificVersion](), mo.None[types.AsyncJobType]())
+		return fsm.NextState()
+	}
+
+	BeforeEach(func() {
+		fakeClock = clock.NewFakeClock()
+		fakeClock.SetNow(10 * 60 * 1000)
+		runtimeConfig = &config.ApplicationRuntimeConfig{ZoneId: CURRENT_ZONE, ZoneStaleThreshold: time.Minute}
+		reachability = peers.NewFakeReachability()
+		reachability.AddZones("zone-b", "zone-c", "zone-d")
+
+		application = makeApplication()
+		application.Spec.Zones = 2
+		application.Status.Ownership.Placements = []v1.Placement{
+			{Zone: "zone-b", NodeAffinity: []string{"node-1"}},
+			{Zone: CURRENT_ZONE},
+		}
+		application.Status.Zones = []v1.ZoneStatus{
+			{
+				ZoneId:            "zone-b",
+				LastHeartbeatTime: heartbeatAgo(30 * time.Second),
+				Conditions: []v1.ConditionStatus{
+					{Type: v1.LocalConditionType, ZoneId: "zone-b", Status: string(health.HealthStatusHealthy)},
+				},
+			},
+		}
+	})
+
+	It("should not fail when a placement zone with healthy status is unreachable", func() {
+		reachability.SetUnreachable("zone-b", true)
+
+		result := nextState()
+		Expect(result.NextState).NotTo(Equal(mo.Some(v1.FailureGlobalState)))
+		Expect(result.Placements.IsAbsent()).To(BeTrue())
+	})
+
+	It("should keep placements of zones with recent heartbeats", func() {
+		result := nextState()
+		Expect(result.Placements.IsAbsent()).To(BeTrue())
+		Expect(result.ConditionsToAdd.IsAbsent()).To(BeTrue())
+	})
+
+	It("should replace a stale placement zone", func() {
+		application.Status.Zones[0].LastHeartbeatTime = heartbeatAgo(2 * time.Minute)
+		reachability.SetUnreachable("zone-c", true)
+
+		result := nextState()
+		Expect(result.NextState).To(Equal(mo.Some(v1.RelocationGlobalState)))
+		Expect(result.Placements).To(Equal(mo.Some([]v1.Placement{
+			{Zone: "zone-b", NodeAffinity: []string{"node-1"}},
+			{Zone: CURRENT_ZONE},
+			{Zone: "zone-d", Replaces: "zone-b"},
+		})))
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Type).To(Equal(v1.RelocationConditionType))
+		Expect(condition.Status).To(Equal(string(v1.RelocationStatusInProgress)))
+		Expect(condition.Reason).To(Equal("ZoneStale"))
+		Expect(condition.Msg).To(Equal("Relocating stale zones: zone-b to zone-d. Scorer LeastLoaded, scores: zone-d=1.00"))
+	})
+
+	It("should not replace zones without heartbeat or when disabled", func() {
+		application.Status.Zones[0].LastHeartbeatTime = nil
+		Expect(nextState().Placements.IsAbsent()).To(BeTrue())
+
+		application.Status.Zones[0].LastHeartbeatTime = heartbeatAgo(2 * time.Minute)
+		runtimeConfig.ZoneStaleThreshold = 0
+		Expect(nextState().Placements.IsAbsent()).To(BeTrue())
+	})
+
+	It("should report a failure when no zone can replace the stale zone", func() {
+		application.Status.Zones[0].LastHeartbeatTime = heartbeatAgo(2 * time.Minute)
+		reachability = peers.NewFakeReachability()
+
+		result := nextState()
+		Expect(result.NextState.IsAbsent()).To(BeTrue())
+		Expect(result.Placements.IsAbsent()).To(BeTrue())
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Status).To(Equal(string(v1.PlacementStatusFailure)))
+		Expect(condition.Reason).To(Equal("ZoneStale"))
+		Expect(condition.Msg).To(Equal("No zone available to replace stale zones zone-b"))
+	})
+
+	It("should record the missing replacement once and go on with the other states", func() {
+		application.Status.Zones[0].LastHeartbeatTime = heartbeatAgo(2 * time.Minute)
+		application.Status.Zones[0].Conditions[0].Status = string(health.HealthStatusDegraded)
+		reachability = peers.NewFakeReachability()
+		condition := nextState().ConditionsToAdd.MustGet()
+		application.Status.Zones = append(application.Status.Zones, v1.ZoneStatus{
+			ZoneId:     CURRENT_ZONE,
+			Conditions: []v1.ConditionStatus{*condition},
+		})
+
+		result := nextState()
+		Expect(result.ConditionsToAdd.IsAbsent()).To(BeTrue())
+		Expect(result.NextState).To(Equal(mo.Some(v1.FailureGlobalState)))
+	})
+
+	It("should replace a zone which requests relocation as a recover action", func() {
+		application.Status.Zones[0].Conditions = append(application.Status.Zones[0].Conditions, v1.ConditionStatus{
+			Type:   v1.RemediationConditionType,
+			ZoneId: "zone-b",
+			Status: string(v1.RemediationStatusAttempted),
+			Reason: string(v1.RecoverActionRelocate),
+		})
+		reachability.SetUnreachable("zone-c", true)
+
+		result := nextState()
+		Expect(result.Placements).To(Equal(mo.Some([]v1.Placement{
+			{Zone: "zone-b", NodeAffinity: []string{"node-1"}},
+			{Zone: CURRENT_ZONE},
+			{Zone: "zone-d", Replaces: "zone-b"},
+		})))
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Reason).To(Equal("RelocationRequested"))
+		Expect(condition.Msg).To(Equal("Relocating zones requesting relocation: zone-b to zone-d. Scorer LeastLoaded, scores: zone-d=1.00"))
+
+		application.Status.Zones[0].Conditions[1].Status = string(v1.RemediationStatusRecovered)
+		Expect(nextState().Placements.IsAbsent()).To(BeTrue())
+	})
+
+	It("should rewrite placements and bump the epoch", func() {
+		application.Status.Zones[0].LastHeartbeatTime = heartbeatAgo(2 * time.Minute)
+
+		updated, _ := globalStateMachine(application, runtimeConfig, reachability, fakeClock, nil, true, mo.None[*types.SpecificVersion](), mo.None[types.AsyncJobType]())
+		Expect(updated).To(BeTrue())
+		Expect(application.Status.Ownership.Placements).To(Equal([]v1.Placement{
+			{Zone: "zone-b", NodeAffinity: []string{"node-1"}},
+			{Zone: CURRENT_ZONE},
+			{Zone: "zone-c", Replaces: "zone-b"},
+		}))
+		Expect(application.Status.Ownership.Epoch).To(Equal(int64(2)))
+		Expect(application.Status.Ownership.State).To(Equal(v1.RelocationGlobalState))
+	})
+})
+
+var _ = Describe("GlobalFSM make-before-break relocation", func() {
+	var (
+		application   *v1.AnyApplication
+		runtimeConfig *config.ApplicationRuntimeConfig
+		fakeClock     *clock.FakeClock
+		reachability  *peers.FakeReachability
+	)
+
+	nextState := func() types.NextStateResult {
+		fsm := NewGlobalFSM(application, runtimeConfig, reachability, fakeClock, nil, true, mo.None[*types.SpecificVersion](), mo.None[types.AsyncJobType]())
+		return fsm.NextState()
+	}
+
+	relocationCondition := func(status v1.RelocationStatus, startedAgo time.Duration) v1.ConditionStatus {
+		return v1.ConditionStatus{
+			Type:               v1.RelocationConditionType,
+			ZoneId:             CURRENT_ZONE,
+			Status:             string(status),
+			LastTransitionTime: metav1.NewTime(fakeClock.NowTime().Add(-startedAgo)),
+			Reason:             "ZoneStale",
+		}
+	}
+
+	localCondition := func(zoneId string, status health.HealthStatusCode) v1.ZoneStatus {
+		return v1.ZoneStatus{
+			ZoneId: zoneId,
+			Conditions: []v1.ConditionStatus{
+				{Type: v1.LocalConditionType, ZoneId: zoneId, Status: string(status)},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		fakeClock = clock.NewFakeClock()
+		fakeClock.SetNow(60 * 60 * 1000)
+		runtimeConfig = &config.ApplicationRuntimeConfig{
+			ZoneId:             CURRENT_ZONE,
+			ZoneStaleThreshold: time.Minute,
+			RelocationTimeout:  10 * time.Minute,
+		}
+		reachability = peers.NewFakeReachability()
+		reachability.AddZones("zone-b", "zone-c")
+
+		application = makeApplication()
+		application.Status.Ownership.Placements = []v1.Placement{
+			{Zone: "zone-b"},
+			{Zone: "zone-c", Replaces: "zone-b"},
+		}
+		application.Status.Zones = []v1.ZoneStatus{
+			{
+				ZoneId:     CURRENT_ZONE,
+				Conditions: []v1.ConditionStatus{relocationCondition(v1.RelocationStatusInProgress, time.Minute)},
+			},
+			localCondition("zone-b", health.HealthStatusHealthy),
+		}
+	})
+
+	It("should keep the replaced zone until the new zone is healthy", func() {
+		application.Status.Zones = append(application.Status.Zones, localCondition("zone-c", health.HealthStatusProgressing))
+
+		result := nextState()
+		Expect(result.NextState).To(Equal(mo.Some(v1.RelocationGlobalState)))
+		Expect(result.Placements.IsAbsent()).To(BeTrue())
+		Expect(result.ConditionsToAdd.IsAbsent()).To(BeTrue())
+	})
+
+	It("should release the replaced zone once the new zone is healthy", func() {
+		application.Status.Zones = append(application.Status.Zones, localCondition("zone-c", health.HealthStatusHealthy))
+
+		result := nextState()
+		Expect(result.Placements).To(Equal(mo.Some([]v1.Placement{{Zone: "zone-c"}})))
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Type).To(Equal(v1.RelocationConditionType))
+		Expect(condition.Status).To(Equal(string(v1.RelocationStatusDone)))
+		Expect(condition.Reason).To(Equal("ZoneStale"))
+		Expect(condition.Msg).To(Equal("Relocated zone-b to zone-c"))
+	})
+
+	It("should roll back when the new zone does not become healthy in time", func() {
+		application.Status.Zones[0].Conditions[0] = relocationCondition(v1.RelocationStatusInProgress, 11*time.Minute)
+
+		result := nextState()
+		Expect(result.Placements).To(Equal(mo.Some([]v1.Placement{{Zone: "zone-b"}})))
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Status).To(Equal(string(v1.RelocationStatusRolledBack)))
+		Expect(condition.Reason).To(Equal("RelocationTimeout"))
+		Expect(condition.Msg).To(Equal("Zones zone-c did not report healthy within 10m0s, relocation rolled back"))
+	})
+
+	It("should wait without timeout when disabled", func() {
+		runtimeConfig.RelocationTimeout = 0
+		application.Status.Zones[0].Conditions[0] = relocationCondition(v1.RelocationStatusInProgress, 11*time.Minute)
+
+		Expect(nextState().Placements.IsAbsent()).To(BeTrue())
+	})
+
+	It("should record the start of a relocation which is not tracked", func() {
+		application.Status.Zones = application.Status.Zones[1:]
+
+		result := nextState()
+		Expect(result.Placements.IsAbsent()).To(BeTrue())
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Status).To(Equal(string(v1.RelocationStatusInProgress)))
+		Expect(condition.LastTransitionTime).To(Equal(fakeClock.NowTime()))
+		Expect(condition.Msg).To(Equal("Relocating to zone-c"))
+	})
+
+	It("should not relocate stale zones again right after a rollback", func() {
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone-b"}}
+		application.Status.Zones[0].Conditions[0] = relocationCondition(v1.RelocationStatusRolledBack, time.Minute)
+		stale := metav1.NewTime(fakeClock.NowTime().Add(-2 * time.Minute))
+		application.Status.Zones[1].LastHeartbeatTime = &stale
+
+		Expect(nextState().Placements.IsAbsent()).To(BeTrue())
+
+		application.Status.Zones[0].Conditions[0] = relocationCondition(v1.RelocationStatusRolledBack, 11*time.Minute)
+		Expect(nextState().Placements).To(Equal(mo.Some([]v1.Placement{
+			{Zone: "zone-b"},
+			{Zone: "zone-c", Replaces: "zone-b"},
+		})))
+	})
+})
+
+var _ = Describe("GlobalFSM global placement", func() {
+	var (
+		application   *v1.AnyApplication
+		runtimeConfig *config.ApplicationRuntimeConfig
+		reachability  *peers.FakeReachability
+	)
+
+	BeforeEach(func() {
+		runtimeConfig = &config.ApplicationRuntimeConfig{ZoneId: CURRENT_ZONE}
+		reachability = peers.NewFakeReachability()
+		reachability.SetZone(types.ZoneInfo{ZoneId: CURRENT_ZONE, Applications: 3})
+		reachability.SetZone(types.ZoneInfo{ZoneId: "zone-b", Applications: 1})
+		reachability.SetZone(types.ZoneInfo{ZoneId: "zone-c", Applications: 0})
+
+		application = makeApplication()
+		application.Spec.Zones = 2
+		application.Spec.PlacementStrategy.Strategy = v1.PlacementStrategyGlobal
+	})
+
+	It("should place the application in the best scored zones", func() {
+		fsm := NewGlobalFSM(application, runtimeConfig, reachability, clock.NewFakeClock(), nil, false, mo.None[*types.SpecificVersion](), mo.None[types.AsyncJobType]())
+		result := fsm.NextState()
+
+		Expect(result.NextState).To(Equal(mo.Some(v1.PlacementGlobalState)))
+		Expect(result.Placements).To(Equal(mo.Some([]v1.Placement{{Zone: "zone-c"}, {Zone: "zone-b"}})))
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Status).To(Equal(string(v1.PlacementStatusDone)))
+		Expect(condition.Msg).To(Equal(
+			"Scorer LeastLoaded placed the application in zone-c, zone-b. Scores: zone-c=1.00, zone-b=0.50, zone=0.25"))
+	})
+
+	It("should fail when the scorer finds no zone", func() {
+		application.Spec.PlacementStrategy.Scorer = v1.PlacementScorerStatic
+		application.Spec.PlacementStrategy.Zones = []string{"zone-x"}
+
+		fsm := NewGlobalFSM(application, runtimeConfig, reachability, clock.NewFakeClock(), nil, false, mo.None[*types.SpecificVersion](), mo.None[types.AsyncJobType]())
+		result := fsm.NextState()
+
+		Expect(result.NextState).To(Equal(mo.Some(v1.FailureGlobalState)))
+		Expect(result.Placements.IsAbsent()).To(BeTrue())
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Status).To(Equal(string(v1.PlacementStatusFailure)))
+		Expect(condition.Msg).To(Equal("Scorer Static found no zone for the application"))
+	})
+})
+
+var _ = Describe("GlobalFSM progressive rollout", func() {
+	var (
+		application   *v1.AnyApplication
+		runtimeConfig *config.ApplicationRuntimeConfig
+		reachability  *peers.FakeReachability
+	)
+
+	nextState := func(resolvedVersion string) types.NextStateResult {
+		version, err := types.NewSpecificVersion(resolvedVersion)
+		Expect(err).NotTo(HaveOccurred())
+		fsm := NewGlobalFSM(application, runtimeConfig, reachability, clock.NewFakeClock(), nil, true, mo.Some(version), mo.None[types.AsyncJobType]())
+		return fsm.NextState()
+	}
+
+	zoneStatus := func(zoneId string, chartVersion string, status health.HealthStatusCode) v1.ZoneStatus {
+		return v1.ZoneStatus{
+			ZoneId:       zoneId,
+			ChartVersion: chartVersion,
+			Conditions: []v1.ConditionStatus{
+				{Type: v1.LocalConditionType, ZoneId: zoneId, Status: string(status)},
+			},
+		}
+	}
+
+	progressing := func(wave int, zones ...string) *v1.RolloutStatus {
+		return &v1.RolloutStatus{
+			Version:         "2.0.0",
+			PreviousVersion: "1.0.0",
+			Phase:           v1.RolloutPhaseProgressing,
+			Wave:            wave,
+			Zones:           zones,
+		}
+	}
+
+	BeforeEach(func() {
+		runtimeConfig = &config.ApplicationRuntimeConfig{ZoneId: CURRENT_ZONE}
+		reachability = peers.NewFakeReachability()
+		reachability.AddZones("zone-b", "zone-c", "zone-d")
+
+		application = makeApplication()
+		application.Spec.Zones = 3
+		application.Spec.UpgradeStrategy.Rollout = &v1.RolloutSpec{CanaryZone: "zone-c", BatchSize: 2}
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone-b"}, {Zone: "zone-c"}, {Zone: "zone-d"}}
+		application.Status.Zones = []v1.ZoneStatus{
+			zoneStatus("zone-b", "1.0.0", health.HealthStatusHealthy),
+			zoneStatus("zone-c", "1.0.0", health.HealthStatusHealthy),
+			zoneStatus("zone-d", "1.0.0", health.HealthStatusHealthy),
+		}
+	})
+
+	It("should install the first version in all zones at once", func() {
+		application.Status.Zones = nil
+
+		result := nextState("1.0.0")
+		Expect(result.NextState.IsAbsent()).To(BeTrue())
+		Expect(result.Rollout).To(Equal(mo.Some(&v1.RolloutStatus{
+			Version: "1.0.0",
+			Phase:   v1.RolloutPhaseCompleted,
+			Zones:   []string{"zone-b", "zone-c", "zone-d"},
+			Message: "Installing version 1.0.0",
+		})))
+	})
+
+	It("should start with the canary zone when a new version is resolved", func() {
+		application.Status.Ownership.Rollout = &v1.RolloutStatus{Version: "1.0.0", Phase: v1.RolloutPhaseCompleted}
+
+		result := nextState("2.0.0")
+		rollout := progressing(1, "zone-c")
+		rollout.Message = "Rolling out version 2.0.0 to canary zone zone-c"
+		Expect(result.Rollout).To(Equal(mo.Some(rollout)))
+	})
+
+	It("should roll out from the version the zones report when no rollout was recorded", func() {
+		application.Spec.UpgradeStrategy.Rollout = nil
+
+		result := nextState("2.0.0")
+		rollout := progressing(1, "zone-b")
+		rollout.Message = "Rolling out version 2.0.0 to canary zone zone-b"
+		Expect(result.Rollout).To(Equal(mo.Some(rollout)))
+	})
+
+	It("should wait until the canary zone reports the version healthy", func() {
+		application.Status.Ownership.Rollout = progressing(1, "zone-c")
+		application.Status.Zones[1] = zoneStatus("zone-c", "2.0.0", health.HealthStatusProgressing)
+
+		result := nextState("2.0.0")
+		Expect(result.Rollout.IsAbsent()).To(BeTrue())
+		Expect(result.NextState).To(Equal(mo.Some(v1.OperationalGlobalState)))
+	})
+
+	It("should admit the next batch once the canary zone is healthy", func() {
+		application.Status.Ownership.Rollout = progressing(1, "zone-c")
+		application.Status.Zones[1] = zoneStatus("zone-c", "2.0.0", health.HealthStatusHealthy)
+
+		result := nextState("2.0.0")
+		rollout := progressing(2, "zone-c", "zone-b", "zone-d")
+		rollout.Message = "Rolling out version 2.0.0 to zones zone-b, zone-d, wave 2"
+		Expect(result.Rollout).To(Equal(mo.Some(rollout)))
+	})
+
+	It("should complete once all zones report the version healthy", func() {
+		application.Status.Ownership.Rollout = progressing(2, "zone-c", "zone-b", "zone-d")
+		application.Status.Zones = []v1.ZoneStatus{
+			zoneStatus("zone-b", "2.0.0", health.HealthStatusHealthy),
+			zoneStatus("zone-c", "2.0.0", health.HealthStatusHealthy),
+			zoneStatus("zone-d", "2.0.0", health.HealthStatusHealthy),
+		}
+
+		result := nextState("2.0.0")
+		rollout := progressing(2, "zone-c", "zone-b", "zone-d")
+		rollout.Phase = v1.RolloutPhaseCompleted
+		rollout.Message = "Rolled out version 2.0.0 to all zones"
+		Expect(result.Rollout).To(Equal(mo.Some(rollout)))
+	})
+
+	It("should halt when an admitted zone fails", func() {
+		application.Status.Ownership.Rollout = progressing(1, "zone-c")
+		application.Status.Zones[1] = zoneStatus("zone-c", "2.0.0", health.HealthStatusDegraded)
+
+		result := nextState("2.0.0")
+		rollout := progressing(1, "zone-c")
+		rollout.Phase = v1.RolloutPhaseHalted
+		rollout.Message = "Zone zone-c failed on version 2.0.0, rollout halted: application is Degraded"
+		Expect(result.Rollout).To(Equal(mo.Some(rollout)))
+
+		application.Status.Ownership.Rollout = rollout
+		Expect(nextState("2.0.0").Rollout.IsAbsent()).To(BeTrue())
+	})
+
+	It("should roll out a newer version from the previous version after a halt", func() {
+		rollout := progressing(1, "zone-c")
+		rollout.Phase = v1.RolloutPhaseHalted
+		application.Status.Ownership.Rollout = rollout
+
+		result := nextState("2.0.1")
+		Expect(result.Rollout).To(Equal(mo.Some(&v1.RolloutStatus{
+			Version:         "2.0.1",
+			PreviousVersion: "1.0.0",
+			Phase:           v1.RolloutPhaseProgressing,
+			Wave:            1,
+			Zones:           []string{"zone-c"},
+			Message:         "Rolling out version 2.0.1 to canary zone zone-c",
+		})))
+	})
+
+	It("should keep zones which are not admitted on the previous version", func() {
+		rollout := progressing(1, "zone-c")
+		Expect(rollout.TargetVersion("zone-c")).To(Equal("2.0.0"))
+		Expect(rollout.TargetVersion("zone-b")).To(Equal("1.0.0"))
+
+		rollout.Phase = v1.RolloutPhaseCompleted
+		Expect(rollout.TargetVersion("zone-b")).To(Equal("2.0.0"))
+	})
+})
+
+var _ = Describe("Published target version", func() {
+	var (
+		application   *v1.AnyApplication
+		runtimeConfig *config.ApplicationRuntimeConfig
+		fakeClock     *clock.FakeClock
+	)
+
+	BeforeEach(func() {
+		fakeClock = clock.NewFakeClock()
+		runtimeConfig = &config.ApplicationRuntimeConfig{ZoneId: CURRENT_ZONE}
+		application = makeApplication()
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: CURRENT_ZONE}}
+	})
+
+	It("should publish the version resolved by the owner", func() {
+		version, _ := types.NewSpecificVersion("1.2.0")
+
+		updated, _ := globalStateMachine(application, runtimeConfig, peers.NewFakeReachability(), fakeClock, nil, true,
+			mo.Some(version), mo.None[types.AsyncJobType]())
+		Expect(updated).To(BeTrue())
+		Expect(application.Status.Ownership.TargetVersion).To(Equal("1.2.0"))
+		Expect(application.Status.Ownership.Rollout.Version).To(Equal("1.2.0"))
+	})
+
+	It("should report the zone which cannot fetch the published version", func() {
+		unavailable := &types.VersionUnavailableError{Version: "1.2.0", Err: errors.New("not found")}
+
+		Expect(updateTargetVersionCondition(&application.Status, mo.Some(unavailable), fakeClock, runtimeConfig)).To(BeTrue())
+		condition, found := findCondition(&application.Status, v1.TargetVersionConditionType, CURRENT_ZONE)
+		Expect(found).To(BeTrue())
+		Expect(condition.Status).To(Equal(string(v1.TargetVersionStatusUnavailable)))
+		Expect(condition.Reason).To(Equal("VersionUnavailable"))
+		Expect(condition.Msg).To(Equal("Chart version 1.2.0 is not available: not found"))
+
+		Expect(updateTargetVersionCondition(&application.Status, mo.Some(unavailable), fakeClock, runtimeConfig)).To(BeFalse())
+
+		application.Status.Ownership.TargetVersion = "1.2.0"
+		Expect(updateTargetVersionCondition(&application.Status, mo.None[*types.VersionUnavailableError](), fakeClock, runtimeConfig)).To(BeTrue())
+		condition, found = findCondition(&application.Status, v1.TargetVersionConditionType, CURRENT_ZONE)
+		Expect(found).To(BeTrue())
+		Expect(condition.Status).To(Equal(string(v1.TargetVersionStatusAvailable)))
+		Expect(condition.Msg).To(Equal("Chart version 1.2.0 is available"))
+
+		Expect(updateTargetVersionCondition(&application.Status, mo.None[*types.VersionUnavailableError](), fakeClock, runtimeConfig)).To(BeFalse())
+	})
+})
+
+var _ = Describe("GlobalFSM scaling", func() {
+	var (
+		application   *v1.AnyApplication
+		runtimeConfig *config.ApplicationRuntimeConfig
+		reachability  *peers.FakeReachability
+	)
+
+	nextState := func() types.NextStateResult {
+		fsm := NewGlobalFSM(application, runtimeConfig, reachability, clock.NewFakeClock(), nil, true, mo.None[*types.SpecificVersion](), mo.None[types.AsyncJobType]())
+		return fsm.NextState()
+	}
+
+	healthy := func(zoneId string) v1.ZoneStatus {
+		return v1.ZoneStatus{
+			ZoneId: zoneId,
+			Conditions: []v1.ConditionStatus{
+				{Type: v1.LocalConditionType, ZoneId: zoneId, Status: string(health.HealthStatusHealthy)},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		runtimeConfig = &config.ApplicationRuntimeConfig{ZoneId: CURRENT_ZONE}
+		reachability = peers.NewFakeReachability()
+		reachability.SetZone(types.ZoneInfo{ZoneId: CURRENT_ZONE, Applications: 3})
+		reachability.SetZone(types.ZoneInfo{ZoneId: "zone-b", Applications: 1})
+		reachability.SetZone(types.ZoneInfo{ZoneId: "zone-c", Applications: 0})
+		reachability.SetZone(types.ZoneInfo{ZoneId: "zone-d", Applications: 2})
+
+		application = makeApplication()
+		application.Spec.PlacementStrategy.Strategy = v1.PlacementStrategyGlobal
+		application.Status.Ownership.State = v1.OperationalGlobalState
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: CURRENT_ZONE}}
+		application.Status.Zones = []v1.ZoneStatus{healthy(CURRENT_ZONE)}
+	})
+
+	It("should keep placements matching the number of zones", func() {
+		result := nextState()
+		Expect(result.Placements.IsAbsent()).To(BeTrue())
+		Expect(result.NextState).To(Equal(mo.Some(v1.OperationalGlobalState)))
+	})
+
+	It("should add the best scored zones when scaled up", func() {
+		application.Spec.Zones = 3
+
+		result := nextState()
+		Expect(result.NextState).To(Equal(mo.Some(v1.RelocationGlobalState)))
+		Expect(result.Placements).To(Equal(mo.Some([]v1.Placement{{Zone: CURRENT_ZONE}, {Zone: "zone-c"}, {Zone: "zone-b"}})))
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Status).To(Equal(string(v1.PlacementStatusDone)))
+		Expect(condition.Reason).To(Equal("ScaledUp"))
+		Expect(condition.Msg).To(Equal(
+			"Scaled from 1 to 3 zones, added zone-c, zone-b. Scorer LeastLoaded, scores: zone-c=1.00, zone-b=0.50, zone-d=0.33"))
+	})
+
+	It("should record the failure once when no zone is available to scale up", func() {
+		application.Spec.Zones = 2
+		reachability = peers.NewFakeReachability()
+
+		result := nextState()
+		Expect(result.Placements.IsAbsent()).To(BeTrue())
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Status).To(Equal(string(v1.PlacementStatusFailure)))
+		Expect(condition.Reason).To(Equal("ScaleUpFailed"))
+		Expect(condition.Msg).To(Equal("Scorer LeastLoaded found no zone to scale from 1 to 2 zones"))
+
+		application.Status.Zones[0].Conditions = append(application.Status.Zones[0].Conditions, *condition)
+		result = nextState()
+		Expect(result.ConditionsToAdd.IsAbsent()).To(BeTrue())
+		Expect(result.NextState).To(Equal(mo.Some(v1.OperationalGlobalState)))
+	})
+
+	It("should remove unhealthy zones first when scaled down", func() {
+		application.Spec.Zones = 2
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: CURRENT_ZONE}, {Zone: "zone-b"}, {Zone: "zone-c"}}
+		application.Status.Zones = []v1.ZoneStatus{healthy(CURRENT_ZONE), {ZoneId: "zone-b"}, healthy("zone-c")}
+
+		result := nextState()
+		Expect(result.NextState).To(Equal(mo.Some(v1.RelocationGlobalState)))
+		Expect(result.Placements).To(Equal(mo.Some([]v1.Placement{{Zone: CURRENT_ZONE}, {Zone: "zone-c"}})))
+		condition := result.ConditionsToAdd.MustGet()
+		Expect(condition.Reason).To(Equal("ScaledDown"))
+		Expect(condition.Msg).To(Equal("Scaled from 3 to 2 zones, removed zone-b"))
+	})
+
+	It("should remove the most recently added zones and keep the owner zone when scaled down", func() {
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone-b"}, {Zone: CURRENT_ZONE}, {Zone: "zone-c"}}
+		application.Status.Zones = []v1.ZoneStatus{healthy(CURRENT_ZONE), healthy("zone-b"), healthy("zone-c")}
+
+		result := nextState()
+		Expect(result.Placements).To(Equal(mo.Some([]v1.Placement{{Zone: CURRENT_ZONE}})))
+		Expect(result.ConditionsToAdd.MustGet().Msg).To(Equal("Scaled from 3 to 1 zones, removed zone-c, zone-b"))
+	})
+})
+
+var _ = Describe("GlobalFSM failure recovery", func() {
+	It("should return to operational once the failures clear", func() {
+		application := makeApplication()
+		application.Status.Ownership.State = v1.FailureGlobalState
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: CURRENT_ZONE}}
+		application.Status.Zones = []v1.ZoneStatus{
+			{
+				ZoneId: CURRENT_ZONE,
+				Conditions: []v1.ConditionStatus{
+					{Type: v1.DeploymentConditionType, ZoneId: CURRENT_ZONE, Status: string(v1.DeploymentStatusFailure)},
+				},
+			},
+		}
+		runtimeConfig := &config.ApplicationRuntimeConfig{ZoneId: CURRENT_ZONE}
+		reachability := peers.NewFakeReachability()
+		reachability.AddZones(CURRENT_ZONE)
+		nextState := func() types.NextStateResult {
+			fsm := NewGlobalFSM(application, runtimeConfig, reachability, clock.NewFakeClock(), nil, true, mo.None[*types.SpecificVersion](), mo.None[types.AsyncJobType]())
+			return fsm.NextState()
+		}
+		Expect(nextState().NextState).To(Equal(mo.Some(v1.FailureGlobalState)))
+
+		application.Status.Zones[0].Conditions = []v1.ConditionStatus{
+			{Type: v1.LocalConditionType, ZoneId: CURRENT_ZONE, Status: string(health.HealthStatusHealthy)},
+		}
+		Expect(nextState().NextState).To(Equal(mo.Some(v1.OperationalGlobalState)))
+	})
+})
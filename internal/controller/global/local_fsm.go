@@ -4,8 +4,11 @@
 package global
 
 import (
+	"time"
+
 	"github.com/samber/mo"
 	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
 	"hiro.io/anyapplication/internal/config"
 	types "hiro.io/anyapplication/internal/controller/types"
 )
@@ -14,6 +17,7 @@ type LocalFSM struct {
 	application              *v1.AnyApplication
 	recoverStrategy          *v1.RecoverStrategySpec
 	config                   *config.ApplicationRuntimeConfig
+	clock                    clock.Clock
 	jobFactory               types.AsyncJobFactory
 	applicationPresent       bool
 	applicationDeployed      bool
@@ -26,6 +30,7 @@ type LocalFSM struct {
 func NewLocalFSM(
 	application *v1.AnyApplication,
 	config *config.ApplicationRuntimeConfig,
+	clock clock.Clock,
 	jobFactory types.AsyncJobFactory,
 	applicationPresent bool,
 	applicationDeployed bool,
@@ -39,6 +44,7 @@ func NewLocalFSM(
 		application:              application,
 		recoverStrategy:          recoverStrategy,
 		config:                   config,
+		clock:                    clock,
 		jobFactory:               jobFactory,
 		applicationPresent:       applicationPresent,
 		applicationDeployed:      applicationDeployed,
@@ -52,16 +58,30 @@ func NewLocalFSM(
 func (g *LocalFSM) NextState() types.NextStateResult {
 	status := &g.application.Status
 
+	if resetAt, pending := types.PendingReset(g.application, g.config.ZoneId); pending {
+		return g.handleReset(resetAt)
+	}
+
 	placementsContainZone := placementsContainZone(status, g.config.ZoneId)
 
-	undeployOldVersion := g.applicationPresent && g.newVersion.IsPresent()
+	// in place and blue/green upgrades deploy the new version while the previous one is present,
+	// recreate upgrades undeploy the previous version first
+	inPlace := g.isInPlaceUpgrade()
+	blueGreen := g.application.Spec.UpgradeStrategy.IsBlueGreen()
+	keepsPrevious := inPlace || blueGreen
+	undeployOldVersion := !keepsPrevious && g.applicationPresent && g.newVersion.IsPresent()
+	undeployNonActiveVersions := g.nonActiveVersionsPresent && (!keepsPrevious || !placementsContainZone)
 
-	if !placementsContainZone && g.applicationPresent || g.nonActiveVersionsPresent || undeployOldVersion {
+	if !placementsContainZone && g.applicationPresent || undeployNonActiveVersions || undeployOldVersion {
 		return g.handleUndeploy()
 	}
 
 	if placementsContainZone {
-		if !g.applicationDeployed {
+		// in place upgrades sync the new version over the deployed one, the sync prunes leftovers.
+		// Blue/green upgrades keep the previous versions until their bake time is over.
+		upgradeInPlace := inPlace && (g.newVersion.IsPresent() || g.nonActiveVersionsPresent)
+		upgradeBlueGreen := blueGreen && (g.newVersion.IsPresent() || g.nonActiveVersionsPresent && !g.isBaking())
+		if !g.applicationDeployed || upgradeInPlace || upgradeBlueGreen {
 			return g.handleDeploy()
 		} else {
 			return g.handleOperation()
@@ -72,6 +92,10 @@ func (g *LocalFSM) NextState() types.NextStateResult {
 		if zoneStatus, exists := status.GetStatusFor(g.config.ZoneId); exists {
 			conditionsToRemove := make([]*v1.ConditionStatus, 0)
 			for _, condition := range zoneStatus.Conditions {
+				// the relocation is tracked by the owner regardless of its own placement
+				if condition.Type == v1.RelocationConditionType {
+					continue
+				}
 				conditionsToRemove = append(conditionsToRemove, &condition)
 			}
 			return types.NextStateResult{ConditionsToRemove: conditionsToRemove}
@@ -88,14 +112,10 @@ func (g *LocalFSM) handleDeploy() types.NextStateResult {
 	conditionsToRemove = addConditionToRemoveList(conditionsToRemove, status.Conditions, v1.LocalConditionType, g.config.ZoneId)
 	conditionsToRemove = addConditionToRemoveList(conditionsToRemove, status.Conditions, v1.UndeploymentConditionType, g.config.ZoneId)
 
-	if !g.applicationDeployed || g.newVersion.IsPresent() {
+	if !g.applicationDeployed || g.newVersion.IsPresent() || g.nonActiveVersionsPresent {
 		if !g.isRunning(types.AsyncJobTypeDeploy) {
 			deploymentCondition, found := status.FindCondition(v1.DeploymentConditionType)
-			attemptsExhausted := false
-			if found {
-				attemptsExhausted = deploymentCondition.Status == string(v1.DeploymentStatusFailure) &&
-					deploymentCondition.RetryAttempt >= g.recoverStrategy.MaxRetries
-			}
+			attemptsExhausted := found && g.retriesExhausted(deploymentCondition, string(v1.DeploymentStatusFailure))
 
 			if !attemptsExhausted {
 				version := g.version
@@ -133,12 +153,7 @@ func (g *LocalFSM) handleUndeploy() types.NextStateResult {
 	conditionsToRemove = addConditionToRemoveList(conditionsToRemove, status.Conditions, v1.DeploymentConditionType, g.config.ZoneId)
 
 	undeploymentCondition, found := status.FindCondition(v1.UndeploymentConditionType)
-	attemptsExhausted := false
-
-	if found {
-		attemptsExhausted = undeploymentCondition.Status == string(v1.UndeploymentStatusFailure) &&
-			undeploymentCondition.RetryAttempt >= g.recoverStrategy.MaxRetries
-	}
+	attemptsExhausted := found && g.retriesExhausted(undeploymentCondition, string(v1.UndeploymentStatusFailure))
 
 	if !g.isRunning(types.AsyncJobTypeUndeploy) {
 
@@ -188,8 +203,8 @@ func (g *LocalFSM) handleOperation() types.NextStateResult {
 		}
 	}
 
-	// By default remove deployment conditions
-	if g.isRunning(types.AsyncJobTypeLocalOperation) || len(conditionsToRemove) > 0 {
+	// By default remove deployment conditions, the bake time of blue/green upgrades starts with the deployment
+	if (g.isRunning(types.AsyncJobTypeLocalOperation) || len(conditionsToRemove) > 0) && !g.isBaking() {
 		conditionsToRemove = addConditionToRemoveList(conditionsToRemove, status.Conditions, v1.DeploymentConditionType, g.config.ZoneId)
 	}
 
@@ -198,6 +213,46 @@ func (g *LocalFSM) handleOperation() types.NextStateResult {
 	}
 }
 
+// handleReset clears the failures and retry counters recorded before the requested reset,
+// the jobs start over from the first attempt in the next cycle
+func (g *LocalFSM) handleReset(resetAt time.Time) types.NextStateResult {
+	status := g.application.Status.GetOrCreateStatusFor(g.config.ZoneId)
+
+	conditionsToRemove := make([]*v1.ConditionStatus, 0)
+	for i := range status.Conditions {
+		if types.ClearedByReset(&status.Conditions[i], resetAt) {
+			conditionsToRemove = append(conditionsToRemove, &status.Conditions[i])
+		}
+	}
+	return types.NextStateResult{
+		ConditionsToRemove: conditionsToRemove,
+		ResetAcknowledged:  mo.Some(resetAt),
+	}
+}
+
+// retriesExhausted tells whether the failed job is not retried anymore. Retries start over
+// after the failure cool-down or when a reset of failures is requested.
+func (g *LocalFSM) retriesExhausted(condition *v1.ConditionStatus, failureStatus string) bool {
+	return condition.Status == failureStatus &&
+		condition.RetryAttempt >= g.recoverStrategy.MaxRetries &&
+		!types.RetriesReset(g.application, condition, g.config.FailureCooldown, g.clock.NowTime().Time)
+}
+
+// isInPlaceUpgrade tells whether the new version is synced over the deployed one, the default strategy
+func (g *LocalFSM) isInPlaceUpgrade() bool {
+	strategyType := g.application.Spec.UpgradeStrategy.Type
+	return strategyType == "" || strategyType == v1.UpgradeStrategyInPlace
+}
+
+// isBaking tells whether the previous versions of a blue/green upgrade are still kept
+func (g *LocalFSM) isBaking() bool {
+	if !g.application.Spec.UpgradeStrategy.IsBlueGreen() || !g.nonActiveVersionsPresent {
+		return false
+	}
+	deadline, found := types.BakeDeadline(g.application, g.config.ZoneId)
+	return found && g.clock.NowTime().Time.Before(deadline)
+}
+
 func (g *LocalFSM) isRunning(jobType types.AsyncJobType) bool {
 	return g.runningJobType.OrEmpty() == jobType
 }
@@ -4,9 +4,12 @@
 package global
 
 import (
+	"time"
+
 	"github.com/argoproj/gitops-engine/pkg/health"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
 	"github.com/samber/mo"
 	v1 "hiro.io/anyapplication/api/v1"
 	"hiro.io/anyapplication/internal/clock"
@@ -15,6 +18,7 @@ import (
 	"hiro.io/anyapplication/internal/controller/job"
 	"hiro.io/anyapplication/internal/controller/local"
 	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/peers"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -72,7 +76,7 @@ var _ = Describe("Local Application FSM", func() {
 		version100, _ = types.NewSpecificVersion("1.0.0")
 		newVersion010, _ = types.NewSpecificVersion("0.1.0")
 		globalApplication = NewFromLocalApplication(localApplications, mo.Some(version100),
-			mo.None[*types.SpecificVersion](), fakeClock, &application, &runtimeConfig, logf.Log)
+			mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 	})
 
@@ -210,7 +214,7 @@ var _ = Describe("Local Application FSM", func() {
 			*version100: &localApp,
 		}
 		globalApplication = NewFromLocalApplication(localApplications, mo.Some(version100),
-			mo.None[*types.SpecificVersion](), fakeClock, &application, &runtimeConfig, logf.Log)
+			mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 		Expect(globalApplication.IsDeployed()).To(BeTrue())
 		Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -292,7 +296,7 @@ var _ = Describe("Local Application FSM", func() {
 			*version100: &localApp,
 		}
 		globalApplication = NewFromLocalApplication(localApplications, mo.Some(version100),
-			mo.None[*types.SpecificVersion](), fakeClock, &application, &runtimeConfig, logf.Log)
+			mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 		Expect(globalApplication.IsDeployed()).To(BeTrue())
 		Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -351,7 +355,7 @@ var _ = Describe("Local Application FSM", func() {
 		localApp := local.FakeLocalApplication(&runtimeConfig, version100, fakeClock, true)
 		localApplications := map[types.SpecificVersion]*local.LocalApplication{*version100: &localApp}
 		globalApplication = NewFromLocalApplication(localApplications, mo.Some(version100),
-			mo.None[*types.SpecificVersion](), fakeClock, &application, &runtimeConfig, logf.Log)
+			mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 		Expect(globalApplication.IsDeployed()).To(BeTrue())
 		Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -425,7 +429,7 @@ var _ = Describe("Local Application FSM", func() {
 		localApp := local.FakeLocalApplication(&runtimeConfig, version100, fakeClock, true)
 		localApplications := map[types.SpecificVersion]*local.LocalApplication{*version100: &localApp}
 		globalApplication = NewFromLocalApplication(localApplications, mo.Some(version100),
-			mo.None[*types.SpecificVersion](), fakeClock, &application, &runtimeConfig, logf.Log)
+			mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 		Expect(globalApplication.IsDeployed()).To(BeTrue())
 		Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -474,6 +478,7 @@ var _ = Describe("Local Application FSM", func() {
 		}
 		application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone"}}
 		application.Status.Ownership.Owner = "zone"
+		application.Spec.UpgradeStrategy.Type = v1.UpgradeStrategyRecreate
 		application.Status.Zones = []v1.ZoneStatus{
 			{
 				ZoneId:       "zone",
@@ -485,7 +490,7 @@ var _ = Describe("Local Application FSM", func() {
 		localApp := local.FakeLocalApplication(&runtimeConfig, version100, fakeClock, true)
 		localApplications := map[types.SpecificVersion]*local.LocalApplication{*version100: &localApp}
 		globalApplication = NewFromLocalApplication(localApplications, mo.Some(version100),
-			mo.Some(newVersion010), fakeClock, &application, &runtimeConfig, logf.Log)
+			mo.Some(newVersion010), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 		Expect(globalApplication.IsDeployed()).To(BeTrue())
 		Expect(globalApplication.IsPresent()).To(BeTrue())
@@ -533,6 +538,326 @@ var _ = Describe("Local Application FSM", func() {
 
 	})
 
+	It("deploy new version in place if operational job finds new version", func() {
+
+		operationCondition := v1.ConditionStatus{
+			Type:               v1.LocalConditionType,
+			ZoneId:             "zone",
+			Status:             string(health.HealthStatusHealthy),
+			LastTransitionTime: fakeClock.NowTime(),
+		}
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone"}}
+		application.Status.Ownership.Owner = "zone"
+		application.Status.Zones = []v1.ZoneStatus{
+			{
+				ZoneId:       "zone",
+				ZoneVersion:  1,
+				ChartVersion: "1.0.0",
+				Conditions:   []v1.ConditionStatus{operationCondition},
+			},
+		}
+		localApp := local.FakeLocalApplication(&runtimeConfig, version100, fakeClock, true)
+		localApplications := map[types.SpecificVersion]*local.LocalApplication{*version100: &localApp}
+		globalApplication = NewFromLocalApplication(localApplications, mo.Some(version100),
+			mo.Some(newVersion010), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
+
+		statusResult := globalApplication.DeriveNewStatus(
+			types.FromCondition(operationCondition, types.AsyncJobTypeLocalOperation), jobFactory,
+		)
+		status := statusResult.Status.OrEmpty()
+		Expect(status.Zones).To(Equal([]v1.ZoneStatus{
+			{
+				ZoneId:       "zone",
+				ZoneVersion:  1,
+				ChartVersion: "0.1.0",
+				Conditions: []v1.ConditionStatus{
+					{
+						Type:               v1.DeploymentConditionType,
+						ZoneId:             "zone",
+						Status:             string(v1.DeploymentStatusPull),
+						LastTransitionTime: fakeClock.NowTime(),
+					},
+				},
+			},
+		}))
+		jobs := statusResult.Jobs
+		jobToAdd := jobs.JobsToAdd.OrEmpty()
+		Expect(jobToAdd.GetType()).To(Equal(types.AsyncJobTypeDeploy))
+		Expect(jobs.JobsToRemove).To(Equal(mo.None[types.AsyncJobType]()))
+
+	})
+
+	It("deploy again in place if resources of previous versions are left", func() {
+
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone"}}
+		application.Status.Ownership.Owner = "zone"
+		application.Status.Zones = []v1.ZoneStatus{
+			{
+				ZoneId:       "zone",
+				ZoneVersion:  1,
+				ChartVersion: "0.1.0",
+			},
+		}
+		oldApp := local.FakeLocalApplication(&runtimeConfig, version100, fakeClock, true)
+		newApp := local.FakeLocalApplication(&runtimeConfig, newVersion010, fakeClock, true)
+		localApplications := map[types.SpecificVersion]*local.LocalApplication{
+			*version100:    &oldApp,
+			*newVersion010: &newApp,
+		}
+		globalApplication = NewFromLocalApplication(localApplications, mo.Some(newVersion010),
+			mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
+
+		Expect(globalApplication.IsDeployed()).To(BeTrue())
+		Expect(globalApplication.NonActiveVersionsPresent()).To(BeTrue())
+
+		statusResult := globalApplication.DeriveNewStatus(types.EmptyJobConditions(), jobFactory)
+		jobs := statusResult.Jobs
+		jobToAdd := jobs.JobsToAdd.OrEmpty()
+		Expect(jobToAdd.GetType()).To(Equal(types.AsyncJobTypeDeploy))
+
+	})
+
+	It("undeploy instead of upgrading in place if zone is not placed", func() {
+
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: "otherzone"}}
+		application.Status.Zones = []v1.ZoneStatus{
+			{
+				ZoneId:       "zone",
+				ZoneVersion:  1,
+				ChartVersion: "1.0.0",
+			},
+		}
+		localApp := local.FakeLocalApplication(&runtimeConfig, version100, fakeClock, true)
+		localApplications := map[types.SpecificVersion]*local.LocalApplication{*version100: &localApp}
+		globalApplication = NewFromLocalApplication(localApplications, mo.Some(version100),
+			mo.Some(newVersion010), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
+
+		Expect(globalApplication.IsVersionChanged()).To(BeTrue())
+
+		statusResult := globalApplication.DeriveNewStatus(types.EmptyJobConditions(), jobFactory)
+		jobs := statusResult.Jobs
+		jobToAdd := jobs.JobsToAdd.OrEmpty()
+		Expect(jobToAdd.GetType()).To(Equal(types.AsyncJobTypeUndeploy))
+
+	})
+
+	Context("blue/green upgrade", func() {
+		var deployedAgo func(time.Duration) types.StatusResult
+
+		BeforeEach(func() {
+			application.Spec.UpgradeStrategy = v1.UpgradeStrategySpec{
+				Type:      v1.UpgradeStrategyBlueGreen,
+				BlueGreen: &v1.BlueGreenSpec{BakeTime: metav1.Duration{Duration: 10 * time.Minute}},
+			}
+			application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone"}}
+			application.Status.Ownership.Owner = "zone"
+
+			deployedAgo = func(ago time.Duration) types.StatusResult {
+				application.Status.Zones = []v1.ZoneStatus{
+					{
+						ZoneId:       "zone",
+						ZoneVersion:  1,
+						ChartVersion: "0.1.0",
+						Conditions: []v1.ConditionStatus{
+							{
+								Type:               v1.DeploymentConditionType,
+								ZoneId:             "zone",
+								Status:             string(v1.DeploymentStatusDone),
+								LastTransitionTime: metav1.NewTime(fakeClock.NowTime().Add(-ago)),
+							},
+						},
+					},
+				}
+				oldApp := local.FakeLocalApplication(&runtimeConfig, version100, fakeClock, true)
+				newApp := local.FakeLocalApplication(&runtimeConfig, newVersion010, fakeClock, true)
+				localApplications := map[types.SpecificVersion]*local.LocalApplication{
+					*version100:    &oldApp,
+					*newVersion010: &newApp,
+				}
+				globalApplication = NewFromLocalApplication(localApplications, mo.Some(newVersion010),
+					mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
+				return globalApplication.DeriveNewStatus(types.EmptyJobConditions(), jobFactory)
+			}
+		})
+
+		It("keep the previous version and the deployment condition while baking", func() {
+			statusResult := deployedAgo(time.Minute)
+
+			jobToAdd := statusResult.Jobs.JobsToAdd.OrEmpty()
+			Expect(jobToAdd.GetType()).To(Equal(types.AsyncJobTypeLocalOperation))
+			status := statusResult.Status.OrEmpty()
+			zoneStatus, _ := status.GetStatusFor("zone")
+			_, found := zoneStatus.FindCondition(v1.DeploymentConditionType)
+			Expect(found).To(BeTrue())
+		})
+
+		It("clean up the previous version once the bake time is over", func() {
+			statusResult := deployedAgo(11 * time.Minute)
+
+			jobToAdd := statusResult.Jobs.JobsToAdd.OrEmpty()
+			Expect(jobToAdd.GetType()).To(Equal(types.AsyncJobTypeDeploy))
+		})
+	})
+
+	Context("failure recovery", func() {
+		var failedAgo func(time.Duration, int) types.StatusResult
+
+		BeforeEach(func() {
+			application.Spec.RecoverStrategy.MaxRetries = 1
+			application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone"}}
+
+			failedAgo = func(ago time.Duration, retryAttempt int) types.StatusResult {
+				application.Status.Zones = []v1.ZoneStatus{
+					{
+						ZoneId:       "zone",
+						ZoneVersion:  1,
+						ChartVersion: "1.0.0",
+						Conditions: []v1.ConditionStatus{
+							{
+								Type:               v1.DeploymentConditionType,
+								ZoneId:             "zone",
+								Status:             string(v1.DeploymentStatusFailure),
+								LastTransitionTime: metav1.NewTime(fakeClock.NowTime().Add(-ago)),
+								RetryAttempt:       retryAttempt,
+							},
+						},
+					},
+				}
+				globalApplication = NewFromLocalApplication(localApplications, mo.Some(version100),
+					mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
+				return globalApplication.DeriveNewStatus(types.EmptyJobConditions(), jobFactory)
+			}
+		})
+
+		deploymentRetryAttempt := func(statusResult types.StatusResult) int {
+			jobToAdd := statusResult.Jobs.JobsToAdd.OrEmpty()
+			Expect(jobToAdd.GetType()).To(Equal(types.AsyncJobTypeDeploy))
+			status := statusResult.Status.OrEmpty()
+			zoneStatus, _ := status.GetStatusFor("zone")
+			condition, _ := zoneStatus.FindCondition(v1.DeploymentConditionType)
+			Expect(condition.Status).To(Equal(string(v1.DeploymentStatusPull)))
+			return condition.RetryAttempt
+		}
+
+		It("retry failed deployment with the next attempt", func() {
+			Expect(deploymentRetryAttempt(failedAgo(time.Minute, 0))).To(Equal(1))
+		})
+
+		It("not retry deployment once retries are exhausted", func() {
+			statusResult := failedAgo(time.Minute, 1)
+			Expect(statusResult.Jobs.JobsToAdd.IsAbsent()).To(BeTrue())
+		})
+
+		It("start retries over after the cool-down", func() {
+			runtimeConfig.FailureCooldown = 5 * time.Minute
+
+			Expect(failedAgo(4*time.Minute, 1).Jobs.JobsToAdd.IsAbsent()).To(BeTrue())
+			Expect(deploymentRetryAttempt(failedAgo(6*time.Minute, 1))).To(Equal(0))
+		})
+
+		It("prefer the cool-down of the recover strategy", func() {
+			runtimeConfig.FailureCooldown = 5 * time.Minute
+			application.Spec.RecoverStrategy.Cooldown = metav1.Duration{Duration: 30 * time.Minute}
+
+			Expect(failedAgo(6*time.Minute, 1).Jobs.JobsToAdd.IsAbsent()).To(BeTrue())
+		})
+
+		It("start retries over when a reset of failures is requested after the failure", func() {
+			application.Annotations = map[string]string{
+				v1.ResetFailuresAnnotation: fakeClock.NowTime().Add(-time.Minute).Format(time.RFC3339),
+			}
+			statusResult := failedAgo(2*time.Minute, 1)
+			Expect(statusResult.Jobs.JobsToAdd.IsAbsent()).To(BeTrue())
+			status := statusResult.Status.OrEmpty()
+			Expect(status.ZoneExists("zone")).To(BeFalse())
+
+			application.Status = status
+			Expect(deploymentRetryAttempt(globalApplication.DeriveNewStatus(types.EmptyJobConditions(), jobFactory))).To(Equal(0))
+			Expect(failedAgo(30*time.Second, 1).Jobs.JobsToAdd.IsAbsent()).To(BeTrue())
+		})
+	})
+
+	Context("failure reset", func() {
+		var resetAt time.Time
+
+		BeforeEach(func() {
+			application.Spec.RecoverStrategy.MaxRetries = 1
+			application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone"}}
+			application.Annotations = map[string]string{
+				v1.ResetFailuresAnnotation: fakeClock.NowTime().Add(-time.Minute).Format(time.RFC3339),
+			}
+			resetAt, _ = types.ResetRequestedAt(&application)
+			failedAt := metav1.NewTime(resetAt.Add(-time.Minute))
+			application.Status.Zones = []v1.ZoneStatus{
+				{
+					ZoneId:       "zone",
+					ZoneVersion:  1,
+					ChartVersion: "1.0.0",
+					Conditions: []v1.ConditionStatus{
+						{
+							Type:               v1.LocalConditionType,
+							ZoneId:             "zone",
+							Status:             string(health.HealthStatusDegraded),
+							LastTransitionTime: failedAt,
+						},
+						{
+							Type:               v1.RemediationConditionType,
+							ZoneId:             "zone",
+							Status:             string(v1.RemediationStatusExhausted),
+							LastTransitionTime: failedAt,
+							RetryAttempt:       2,
+						},
+						{
+							Type:               v1.UndeploymentConditionType,
+							ZoneId:             "zone",
+							Status:             string(v1.UndeploymentStatusFailure),
+							LastTransitionTime: metav1.NewTime(resetAt.Add(30 * time.Second)),
+						},
+					},
+				},
+			}
+		})
+
+		nextState := func() types.NextStateResult {
+			fsm := NewLocalFSM(&application, &runtimeConfig, fakeClock, jobFactory, true, true, false,
+				version100, mo.None[*types.SpecificVersion](), mo.None[types.AsyncJobType]())
+			return fsm.NextState()
+		}
+
+		It("clear the failures and retry counters recorded before the reset", func() {
+			result := nextState()
+
+			Expect(result.ConditionsToRemove).To(HaveLen(1))
+			Expect(result.ConditionsToRemove[0].Type).To(Equal(v1.RemediationConditionType))
+			Expect(result.ResetAcknowledged).To(Equal(mo.Some(resetAt)))
+			Expect(result.Jobs.JobsToAdd.IsAbsent()).To(BeTrue())
+		})
+
+		It("acknowledge the reset in the zone status", func() {
+			statusResult := globalApplication.DeriveNewStatus(types.EmptyJobConditions(), jobFactory)
+
+			status := statusResult.Status.OrEmpty()
+			zoneStatus, found := status.GetStatusFor("zone")
+			Expect(found).To(BeTrue())
+			Expect(zoneStatus.LastResetTime).To(Equal(&metav1.Time{Time: resetAt}))
+			_, found = zoneStatus.FindCondition(v1.RemediationConditionType)
+			Expect(found).To(BeFalse())
+			_, found = zoneStatus.FindCondition(v1.UndeploymentConditionType)
+			Expect(found).To(BeTrue())
+		})
+
+		It("not clear the failures again once the reset is acknowledged", func() {
+			application.Status.Zones[0].LastResetTime = &metav1.Time{Time: resetAt}
+
+			result := nextState()
+			Expect(result.ResetAcknowledged.IsAbsent()).To(BeTrue())
+			removed := lo.Map(result.ConditionsToRemove, func(condition *v1.ConditionStatus, _ int) v1.ApplicationConditionType {
+				return condition.Type
+			})
+			Expect(removed).NotTo(ContainElement(v1.RemediationConditionType))
+		})
+	})
+
 	It("let undeployment job to finish if new version is available", func() {
 
 		operationCondition := v1.ConditionStatus{
@@ -550,6 +875,7 @@ var _ = Describe("Local Application FSM", func() {
 
 		application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone"}}
 		application.Status.Ownership.Owner = "zone"
+		application.Spec.UpgradeStrategy.Type = v1.UpgradeStrategyRecreate
 		application.Status.Zones = []v1.ZoneStatus{
 
 			{
@@ -562,7 +888,7 @@ var _ = Describe("Local Application FSM", func() {
 		localApp := local.FakeLocalApplication(&runtimeConfig, version100, fakeClock, true)
 		localApplications := map[types.SpecificVersion]*local.LocalApplication{*version100: &localApp}
 		globalApplication = NewFromLocalApplication(localApplications, mo.Some(newVersion010),
-			mo.None[*types.SpecificVersion](), fakeClock, &application, &runtimeConfig, logf.Log)
+			mo.None[*types.SpecificVersion](), mo.None[*types.SpecificVersion](), mo.None[*types.VersionUnavailableError](), fakeClock, &application, &runtimeConfig, peers.NewFakeReachability(), logf.Log)
 
 		Expect(globalApplication.IsDeployed()).To(BeFalse())
 		Expect(globalApplication.IsPresent()).To(BeFalse())
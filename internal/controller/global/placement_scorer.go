@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package global
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/controller/types"
+)
+
+// ZoneScore is the score of a zone, zones with higher scores are preferred
+type ZoneScore struct {
+	ZoneId string
+	Score  float64
+}
+
+// PlacementScorer ranks the zones an application can be placed in.
+// Zones which must not host the application are left out of the scores.
+type PlacementScorer interface {
+	Name() string
+	Score(application *v1.AnyApplication, zones []types.ZoneInfo) []ZoneScore
+}
+
+// NewPlacementScorer returns the scorer selected by the placement strategy, LeastLoaded by default
+func NewPlacementScorer(spec *v1.PlacementStrategySpec) PlacementScorer {
+	switch spec.Scorer {
+	case v1.PlacementScorerAffinity:
+		return NewAffinityScorer(spec.Affinity)
+	case v1.PlacementScorerSpread:
+		return NewSpreadScorer()
+	case v1.PlacementScorerStatic:
+		return NewStaticScorer(spec.Zones)
+	default:
+		return NewLeastLoadedScorer()
+	}
+}
+
+// LeastLoadedScorer prefers zones with fewer placed applications
+type LeastLoadedScorer struct{}
+
+func NewLeastLoadedScorer() *LeastLoadedScorer {
+	return &LeastLoadedScorer{}
+}
+
+func (s *LeastLoadedScorer) Name() string {
+	return string(v1.PlacementScorerLeastLoaded)
+}
+
+func (s *LeastLoadedScorer) Score(application *v1.AnyApplication, zones []types.ZoneInfo) []ZoneScore {
+	scores := make([]ZoneScore, 0, len(zones))
+	for _, zone := range zones {
+		scores = append(scores, ZoneScore{ZoneId: zone.ZoneId, Score: 1 / float64(1+zone.Applications)})
+	}
+	return scores
+}
+
+// AffinityScorer places applications only in zones having the required labels
+// and prefers zones of the earlier listed regions
+type AffinityScorer struct {
+	affinity *v1.ZoneAffinity
+}
+
+func NewAffinityScorer(affinity *v1.ZoneAffinity) *AffinityScorer {
+	if affinity == nil {
+		affinity = &v1.ZoneAffinity{}
+	}
+	return &AffinityScorer{affinity: affinity}
+}
+
+func (s *AffinityScorer) Name() string {
+	return string(v1.PlacementScorerAffinity)
+}
+
+func (s *AffinityScorer) Score(application *v1.AnyApplication, zones []types.ZoneInfo) []ZoneScore {
+	scores := make([]ZoneScore, 0, len(zones))
+	for _, zone := range zones {
+		if !hasLabels(zone.Labels, s.affinity.Labels) {
+			continue
+		}
+		score := 0.0
+		for i, region := range s.affinity.Regions {
+			if zone.Labels[v1.RegionLabel] == region {
+				score = float64(len(s.affinity.Regions) - i)
+				break
+			}
+		}
+		scores = append(scores, ZoneScore{ZoneId: zone.ZoneId, Score: score})
+	}
+	return scores
+}
+
+// SpreadScorer prefers zones of regions hosting fewer placements of the application.
+// A zone without region label is a region of its own.
+type SpreadScorer struct{}
+
+func NewSpreadScorer() *SpreadScorer {
+	return &SpreadScorer{}
+}
+
+func (s *SpreadScorer) Name() string {
+	return string(v1.PlacementScorerSpread)
+}
+
+func (s *SpreadScorer) Score(application *v1.AnyApplication, zones []types.ZoneInfo) []ZoneScore {
+	regions := make(map[string]string)
+	for _, zone := range zones {
+		regions[zone.ZoneId] = regionOf(zone)
+	}
+	placedPerRegion := make(map[string]int)
+	for _, placement := range application.Status.Ownership.Placements {
+		region, known := regions[placement.Zone]
+		if !known {
+			region = placement.Zone
+		}
+		placedPerRegion[region]++
+	}
+
+	scores := make([]ZoneScore, 0, len(zones))
+	for _, zone := range zones {
+		scores = append(scores, ZoneScore{ZoneId: zone.ZoneId, Score: 1 / float64(1+placedPerRegion[regionOf(zone)])})
+	}
+	return scores
+}
+
+// StaticScorer places applications only in the listed zones, in order of the list
+type StaticScorer struct {
+	zones []string
+}
+
+func NewStaticScorer(zones []string) *StaticScorer {
+	return &StaticScorer{zones: zones}
+}
+
+func (s *StaticScorer) Name() string {
+	return string(v1.PlacementScorerStatic)
+}
+
+func (s *StaticScorer) Score(application *v1.AnyApplication, zones []types.ZoneInfo) []ZoneScore {
+	scores := make([]ZoneScore, 0, len(zones))
+	for _, zone := range zones {
+		for i, zoneId := range s.zones {
+			if zone.ZoneId == zoneId {
+				scores = append(scores, ZoneScore{ZoneId: zone.ZoneId, Score: float64(len(s.zones) - i)})
+				break
+			}
+		}
+	}
+	return scores
+}
+
+// rankZones orders the scores from the best to the worst zone, ties are ordered by zone
+func rankZones(scores []ZoneScore) []ZoneScore {
+	ranked := make([]ZoneScore, len(scores))
+	copy(ranked, scores)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ZoneId < ranked[j].ZoneId
+	})
+	return ranked
+}
+
+func formatScores(scores []ZoneScore) string {
+	if len(scores) == 0 {
+		return "none"
+	}
+	formatted := make([]string, 0, len(scores))
+	for _, score := range scores {
+		formatted = append(formatted, fmt.Sprintf("%s=%.2f", score.ZoneId, score.Score))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func hasLabels(labels map[string]string, required map[string]string) bool {
+	for key, value := range required {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func regionOf(zone types.ZoneInfo) string {
+	if region := zone.Labels[v1.RegionLabel]; region != "" {
+		return region
+	}
+	return zone.ZoneId
+}
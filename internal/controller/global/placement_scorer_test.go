@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package global
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/controller/types"
+)
+
+var _ = Describe("PlacementScorer", func() {
+	var (
+		application *v1.AnyApplication
+		zones       []types.ZoneInfo
+	)
+
+	BeforeEach(func() {
+		application = makeApplication()
+		zones = []types.ZoneInfo{
+			{ZoneId: "zone-a", Labels: map[string]string{v1.RegionLabel: "eu", "gpu": "true"}, Applications: 4},
+			{ZoneId: "zone-b", Labels: map[string]string{v1.RegionLabel: "eu"}, Applications: 1},
+			{ZoneId: "zone-c", Labels: map[string]string{v1.RegionLabel: "us", "gpu": "true"}, Applications: 0},
+			{ZoneId: "zone-d", Applications: 1},
+		}
+	})
+
+	It("should select the scorer of the placement strategy", func() {
+		Expect(NewPlacementScorer(&v1.PlacementStrategySpec{}).Name()).To(Equal("LeastLoaded"))
+		Expect(NewPlacementScorer(&v1.PlacementStrategySpec{Scorer: v1.PlacementScorerAffinity}).Name()).To(Equal("Affinity"))
+		Expect(NewPlacementScorer(&v1.PlacementStrategySpec{Scorer: v1.PlacementScorerSpread}).Name()).To(Equal("Spread"))
+		Expect(NewPlacementScorer(&v1.PlacementStrategySpec{Scorer: v1.PlacementScorerStatic}).Name()).To(Equal("Static"))
+	})
+
+	It("should prefer least loaded zones", func() {
+		scores := rankZones(NewLeastLoadedScorer().Score(application, zones))
+		Expect(formatScores(scores)).To(Equal("zone-c=1.00, zone-b=0.50, zone-d=0.50, zone-a=0.20"))
+	})
+
+	It("should require labels and prefer regions", func() {
+		scorer := NewAffinityScorer(&v1.ZoneAffinity{
+			Labels:  map[string]string{"gpu": "true"},
+			Regions: []string{"us", "eu"},
+		})
+		Expect(formatScores(rankZones(scorer.Score(application, zones)))).To(Equal("zone-c=2.00, zone-a=1.00"))
+
+		Expect(NewAffinityScorer(nil).Score(application, zones)).To(HaveLen(4))
+	})
+
+	It("should spread placements across regions", func() {
+		application.Status.Ownership.Placements = []v1.Placement{{Zone: "zone-a"}, {Zone: "zone-d"}}
+		scores := rankZones(NewSpreadScorer().Score(application, zones))
+		Expect(formatScores(scores)).To(Equal("zone-c=1.00, zone-a=0.50, zone-b=0.50, zone-d=0.50"))
+	})
+
+	It("should score listed zones only in order of the list", func() {
+		scores := rankZones(NewStaticScorer([]string{"zone-d", "zone-x", "zone-a"}).Score(application, zones))
+		Expect(formatScores(scores)).To(Equal("zone-d=3.00, zone-a=1.00"))
+		Expect(formatScores(nil)).To(Equal("none"))
+	})
+})
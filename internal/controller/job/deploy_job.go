@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/cockroachdb/errors"
 	"github.com/go-logr/logr"
 	v1 "hiro.io/anyapplication/api/v1"
 	"hiro.io/anyapplication/internal/clock"
@@ -16,6 +17,9 @@ import (
 	"hiro.io/anyapplication/internal/controller/events"
 	"hiro.io/anyapplication/internal/controller/status"
 	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/helm"
+	"hiro.io/anyapplication/internal/podtemplate"
+	"hiro.io/anyapplication/internal/resources"
 )
 
 type DeployJob struct {
@@ -33,6 +37,8 @@ type DeployJob struct {
 	timeout       time.Duration
 	retryAttempts int
 	attempt       int
+	retryAttempt  int
+	pruneVersions bool
 }
 
 func NewDeployJob(
@@ -53,6 +59,15 @@ func NewDeployJob(
 
 	syncTimeout := config.GetSyncTimeout(application.Spec.SyncPolicy.SyncOptions, runtimeConfig.DefaultSyncTimeout)
 	log = log.WithName("DeployJob")
+
+	// blue/green upgrades keep the previous versions until the bake time after the deployment is over
+	pruneVersions := true
+	if application.Spec.UpgradeStrategy.IsBlueGreen() {
+		deadline, found := types.BakeDeadline(application, runtimeConfig.ZoneId)
+		pruneVersions = found && !clock.NowTime().Time.Before(deadline)
+	}
+	retryAttempt := types.NextRetryAttempt(application, runtimeConfig.ZoneId, v1.DeploymentConditionType,
+		string(v1.DeploymentStatusFailure), runtimeConfig.FailureCooldown, clock.NowTime().Time)
 	return &DeployJob{
 		status:        v1.DeploymentStatusPull,
 		application:   application,
@@ -67,10 +82,16 @@ func NewDeployJob(
 		timeout:       syncTimeout,
 		retryAttempts: 3,
 		attempt:       1,
+		retryAttempt:  retryAttempt,
+		pruneVersions: pruneVersions,
 	}
 }
 
 func (job *DeployJob) Run(jobContext types.AsyncJobContext) {
+	if job.runtimeConfig.CapacityCheck && !job.checkCapacity(jobContext) {
+		return
+	}
+
 	if job.runSyncCycle(jobContext) {
 		return
 	}
@@ -91,6 +112,34 @@ func (job *DeployJob) Run(jobContext types.AsyncJobContext) {
 	}
 
 }
+
+// checkCapacity fails the deployment when the zone cannot fit the application.
+// Errors of the check itself do not block the deployment.
+func (job *DeployJob) checkCapacity(context types.AsyncJobContext) bool {
+	chart, err := context.GetApplications().GetRenderedChart(context.GetGoContext(), job.application, job.version)
+	if err != nil {
+		job.log.Error(err, "Skipping capacity check, failed to render application")
+		return true
+	}
+	podTemplates, err := podtemplate.NewRegistry(job.runtimeConfig.PodTemplates)
+	if err != nil {
+		job.log.Error(err, "Skipping capacity check, invalid pod template configuration")
+		return true
+	}
+	checker := resources.NewCapacityChecker(context.GetKubeClient(), podTemplates, types.InstanceIdLabel)
+	err = checker.Check(context.GetGoContext(), chart)
+
+	var capacityErr *resources.InsufficientCapacityError
+	switch {
+	case errors.As(err, &capacityErr):
+		job.Fail(context, capacityErr.Error(), "InsufficientCapacity")
+		return false
+	case err != nil:
+		job.log.Error(err, "Skipping capacity check")
+	}
+	return true
+}
+
 func (job *DeployJob) runSyncCycle(context types.AsyncJobContext) bool {
 	applications := context.GetApplications()
 
@@ -98,11 +147,25 @@ func (job *DeployJob) runSyncCycle(context types.AsyncJobContext) bool {
 	healthStatus := syncResult.AggregatedStatus.HealthStatus
 
 	if err != nil {
-		job.Fail(context, err.Error(), "SyncError")
+		var valuesErr *helm.ValuesValidationError
+		switch {
+		case errors.Is(err, helm.ErrChartNotVerified):
+			job.Fail(context, err.Error(), "ChartNotVerified")
+		case errors.As(err, &valuesErr):
+			job.Fail(context, valuesErr.Error(), "InvalidValues")
+		default:
+			job.Fail(context, err.Error(), "SyncError")
+		}
 		return true
 	}
 
-	if syncResult.ApplicationResourcesDeployed {
+	if syncResult.ApplicationResourcesDeployed && job.isReadyToSwitch(healthStatus) {
+		if !job.switchServices(context) {
+			return true
+		}
+		if job.pruneVersions {
+			job.prunePreviousVersions(context)
+		}
 		job.Success(context, healthStatus)
 		return true
 	}
@@ -146,6 +209,43 @@ func (job *DeployJob) Fail(jobContext types.AsyncJobContext, msg string, reason
 	job.updateStatus(jobContext)
 }
 
+// isReadyToSwitch holds blue/green deployments until the new version is healthy
+func (job *DeployJob) isReadyToSwitch(healthStatus *health.HealthStatus) bool {
+	if !job.application.Spec.UpgradeStrategy.IsBlueGreen() {
+		return true
+	}
+	return healthStatus != nil && healthStatus.Status == health.HealthStatusHealthy
+}
+
+// switchServices points the shared Services of a blue/green upgrade to the new version
+func (job *DeployJob) switchServices(context types.AsyncJobContext) bool {
+	if !job.application.Spec.UpgradeStrategy.IsBlueGreen() {
+		return true
+	}
+	switched, err := context.GetApplications().SwitchServices(context.GetGoContext(), job.application, job.version)
+	if err != nil {
+		job.Fail(context, err.Error(), "ServiceSwitchError")
+		return false
+	}
+	if switched > 0 {
+		job.log.Info("Switched services to new version", "services", switched, "version", job.version.ToString())
+	}
+	return true
+}
+
+// prunePreviousVersions removes what previous versions left after an upgrade.
+// The deployment succeeds regardless, leftovers are pruned again on the next deployment.
+func (job *DeployJob) prunePreviousVersions(context types.AsyncJobContext) {
+	pruneResult, err := context.GetApplications().PruneVersions(context.GetGoContext(), job.application, job.version)
+	if err != nil {
+		job.log.Error(err, "Failed to prune resources of previous versions")
+		return
+	}
+	if pruneResult.Total > 0 {
+		job.log.Info("Pruned resources of previous versions", "deleted", pruneResult.Deleted, "failed", pruneResult.DeleteFailed)
+	}
+}
+
 func (job *DeployJob) Success(jobContext types.AsyncJobContext, healthStatus *health.HealthStatus) {
 	job.status = v1.DeploymentStatusDone
 	job.msg = "Deployment state changed to '" + string(job.status) + "'. "
@@ -164,6 +264,12 @@ func (job *DeployJob) updateStatus(jobContext types.AsyncJobContext) {
 		job.events,
 	)
 	event := events.Event{Reason: events.LocalStateChangeReason, Msg: job.msg}
+	switch {
+	case job.reason == "Timeout":
+		event = events.Failure(events.DeploymentTimeoutReason, job.msg)
+	case job.reason != "":
+		event = events.Failure(events.DeploymentFailedReason, job.msg)
+	}
 	err := statusUpdater.UpdateCondition(event, job.GetStatus(), v1.UndeploymentConditionType, v1.LocalConditionType)
 	if err != nil {
 		job.log.WithName("StatusUpdater").Error(err, "Failed to update status")
@@ -186,5 +292,6 @@ func (job *DeployJob) GetStatus() v1.ConditionStatus {
 		LastTransitionTime: job.clock.NowTime(),
 		Msg:                job.msg,
 		Reason:             job.reason,
+		RetryAttempt:       job.retryAttempt,
 	}
 }
@@ -16,6 +16,7 @@ import (
 	ctrl_sync "hiro.io/anyapplication/internal/controller/sync"
 	"hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/helm"
+	"hiro.io/anyapplication/internal/peers"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -88,7 +89,7 @@ var _ = Describe("DeployJobUnitests", func() {
 		clusterCache, _ := fixture.NewTestClusterCacheWithOptions([]cache.UpdateSettingsFunc{})
 		fakeCharts := ctrl_sync.NewFakeCharts()
 		applications := ctrl_sync.NewApplications(kubeClient, helmClient,
-			fakeCharts, clusterCache, fakeClock, &runtimeConfig, gitOpsEngine, logf.Log)
+			fakeCharts, clusterCache, fakeClock, &runtimeConfig, peers.NewFakeReachability(), gitOpsEngine, logf.Log)
 
 		jobContext = NewAsyncJobContext(helmClient, kubeClient, ctx, applications)
 
@@ -120,4 +121,18 @@ var _ = Describe("DeployJobUnitests", func() {
 		Expect(deployJob.GetStatus().Msg).To(Equal("Deployment failure: Deployment timed out after 300ms"))
 	})
 
+	It("Deployment should fail when chart keyring is missing", func() {
+		verifiedApplication := application.DeepCopy()
+		verifiedApplication.Spec.Source.HelmSelector.Verify = &v1.ChartVerification{KeyringSecret: "missing"}
+		deployJob = NewDeployJob(verifiedApplication, version, &runtimeConfig, fakeClock, logf.Log, &fakeEvents)
+
+		jobContext, cancel := jobContext.WithCancel()
+		defer cancel()
+
+		go deployJob.Run(jobContext)
+
+		waitForJobStatus(deployJob, string(v1.DeploymentStatusFailure))
+		Expect(deployJob.GetStatus().Reason).To(Equal("ChartNotVerified"))
+	})
+
 })
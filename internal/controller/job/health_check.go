@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	v1 "hiro.io/anyapplication/api/v1"
+)
+
+const (
+	HealthCheckFailedReason        = "HealthCheckFailed"
+	ProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
+)
+
+type healthVerdict int
+
+const (
+	// healthReport reports the observed health status
+	healthReport healthVerdict = iota
+	// healthHold keeps the reported status, the application is within its grace period
+	healthHold
+	// healthFail fails the application
+	healthFail
+)
+
+// healthCheck applies the hysteresis of the application health check to the health
+// observed on consecutive polls
+type healthCheck struct {
+	spec             v1.HealthCheckSpec
+	unhealthyPolls   int
+	unhealthySince   time.Time
+	progressingSince time.Time
+	deadlineExceeded bool
+}
+
+func newHealthCheck(application *v1.AnyApplication, zoneId string) *healthCheck {
+	check := &healthCheck{spec: application.Spec.RecoverStrategy.HealthCheck}
+	zoneStatus, found := application.Status.GetStatusFor(zoneId)
+	if !found {
+		return check
+	}
+	local, found := zoneStatus.FindCondition(v1.LocalConditionType)
+	if !found {
+		return check
+	}
+	// the deadline of a rollout keeps counting when the job is restarted
+	switch {
+	case local.Status == string(health.HealthStatusProgressing):
+		check.progressingSince = local.LastTransitionTime.Time
+	case local.Reason == ProgressDeadlineExceededReason:
+		check.deadlineExceeded = true
+	}
+	return check
+}
+
+// observe records the health status of a poll and returns what to do about it, together with
+// the failure message when the application fails
+func (c *healthCheck) observe(status health.HealthStatusCode, now time.Time) (healthVerdict, string) {
+	switch status {
+	case health.HealthStatusHealthy:
+		c.unhealthyPolls = 0
+		c.unhealthySince = time.Time{}
+		c.progressingSince = time.Time{}
+		c.deadlineExceeded = false
+		return healthReport, ""
+
+	case health.HealthStatusProgressing:
+		c.unhealthyPolls = 0
+		c.unhealthySince = time.Time{}
+		if c.deadlineExceeded {
+			return healthHold, ""
+		}
+		if c.progressingSince.IsZero() {
+			c.progressingSince = now
+		}
+		deadline := c.spec.ProgressDeadline.Duration
+		if deadline > 0 && !now.Before(c.progressingSince.Add(deadline)) {
+			c.deadlineExceeded = true
+			return healthFail, fmt.Sprintf("Application is progressing for longer than %s", deadline)
+		}
+		return healthReport, ""
+
+	default:
+		c.unhealthyPolls++
+		if c.unhealthySince.IsZero() {
+			c.unhealthySince = now
+		}
+		if c.thresholdReached(now) {
+			return healthFail, fmt.Sprintf("Application is %s for %d consecutive polls since %s",
+				status, c.unhealthyPolls, c.unhealthySince.Format(time.RFC3339))
+		}
+		return healthHold, ""
+	}
+}
+
+// reset starts counting the unhealthy polls over, so that a remediated application gets the
+// full grace period to recover
+func (c *healthCheck) reset() {
+	c.unhealthyPolls = 0
+	c.unhealthySince = time.Time{}
+}
+
+func (c *healthCheck) thresholdReached(now time.Time) bool {
+	polls := c.spec.FailureThreshold
+	gracePeriod := c.spec.GracePeriod.Duration
+	if polls <= 0 && gracePeriod <= 0 {
+		return true
+	}
+	return (polls > 0 && c.unhealthyPolls >= polls) ||
+		(gracePeriod > 0 && !now.Before(c.unhealthySince.Add(gracePeriod)))
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "hiro.io/anyapplication/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("HealthCheck", func() {
+	var (
+		application *v1.AnyApplication
+		now         time.Time
+	)
+
+	BeforeEach(func() {
+		application = &v1.AnyApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+		}
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	It("should fail on the first unhealthy poll by default", func() {
+		check := newHealthCheck(application, "zone")
+
+		verdict, msg := check.observe(health.HealthStatusDegraded, now)
+		Expect(verdict).To(Equal(healthFail))
+		Expect(msg).To(Equal("Application is Degraded for 1 consecutive polls since 2025-01-01T00:00:00Z"))
+	})
+
+	It("should fail after the consecutive unhealthy polls", func() {
+		application.Spec.RecoverStrategy.HealthCheck.FailureThreshold = 3
+		check := newHealthCheck(application, "zone")
+
+		Expect(verdictOf(check.observe(health.HealthStatusDegraded, now))).To(Equal(healthHold))
+		Expect(verdictOf(check.observe(health.HealthStatusUnknown, now.Add(time.Second)))).To(Equal(healthHold))
+		Expect(verdictOf(check.observe(health.HealthStatusHealthy, now.Add(2*time.Second)))).To(Equal(healthReport))
+		Expect(verdictOf(check.observe(health.HealthStatusDegraded, now.Add(3*time.Second)))).To(Equal(healthHold))
+		Expect(verdictOf(check.observe(health.HealthStatusDegraded, now.Add(4*time.Second)))).To(Equal(healthHold))
+		Expect(verdictOf(check.observe(health.HealthStatusDegraded, now.Add(5*time.Second)))).To(Equal(healthFail))
+	})
+
+	It("should fail once the grace period passed", func() {
+		application.Spec.RecoverStrategy.HealthCheck.GracePeriod = metav1.Duration{Duration: time.Minute}
+		check := newHealthCheck(application, "zone")
+
+		Expect(verdictOf(check.observe(health.HealthStatusDegraded, now))).To(Equal(healthHold))
+		Expect(verdictOf(check.observe(health.HealthStatusDegraded, now.Add(30*time.Second)))).To(Equal(healthHold))
+		Expect(verdictOf(check.observe(health.HealthStatusDegraded, now.Add(time.Minute)))).To(Equal(healthFail))
+	})
+
+	It("should report progressing until the progress deadline", func() {
+		application.Spec.RecoverStrategy.HealthCheck.ProgressDeadline = metav1.Duration{Duration: 10 * time.Minute}
+		check := newHealthCheck(application, "zone")
+
+		Expect(verdictOf(check.observe(health.HealthStatusProgressing, now))).To(Equal(healthReport))
+		Expect(verdictOf(check.observe(health.HealthStatusProgressing, now.Add(5*time.Minute)))).To(Equal(healthReport))
+
+		verdict, msg := check.observe(health.HealthStatusProgressing, now.Add(10*time.Minute))
+		Expect(verdict).To(Equal(healthFail))
+		Expect(msg).To(Equal("Application is progressing for longer than 10m0s"))
+	})
+
+	It("should count the progress deadline from the reported progressing status", func() {
+		application.Spec.RecoverStrategy.HealthCheck.ProgressDeadline = metav1.Duration{Duration: 10 * time.Minute}
+		application.Status.AddOrUpdate(&v1.ConditionStatus{
+			Type:               v1.LocalConditionType,
+			ZoneId:             "zone",
+			Status:             string(health.HealthStatusProgressing),
+			LastTransitionTime: metav1.NewTime(now),
+		}, "zone")
+		check := newHealthCheck(application, "zone")
+
+		Expect(verdictOf(check.observe(health.HealthStatusProgressing, now.Add(10*time.Minute)))).To(Equal(healthFail))
+	})
+
+	It("should keep the exceeded deadline until the application is healthy", func() {
+		application.Spec.RecoverStrategy.HealthCheck.ProgressDeadline = metav1.Duration{Duration: 10 * time.Minute}
+		application.Status.AddOrUpdate(&v1.ConditionStatus{
+			Type:               v1.LocalConditionType,
+			ZoneId:             "zone",
+			Status:             string(health.HealthStatusDegraded),
+			Reason:             ProgressDeadlineExceededReason,
+			LastTransitionTime: metav1.NewTime(now),
+		}, "zone")
+		check := newHealthCheck(application, "zone")
+
+		Expect(verdictOf(check.observe(health.HealthStatusProgressing, now))).To(Equal(healthHold))
+		Expect(verdictOf(check.observe(health.HealthStatusHealthy, now))).To(Equal(healthReport))
+		Expect(verdictOf(check.observe(health.HealthStatusProgressing, now))).To(Equal(healthReport))
+	})
+})
+
+func verdictOf(verdict healthVerdict, _ string) healthVerdict {
+	return verdict
+}
@@ -23,6 +23,7 @@ import (
 	"hiro.io/anyapplication/internal/controller/sync"
 	"hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/helm"
+	"hiro.io/anyapplication/internal/peers"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2/textlogger"
@@ -139,7 +140,7 @@ var _ = BeforeEach(func() {
 		panic("error " + err.Error())
 	}
 	charts := sync.NewCharts(context.TODO(), helmClient, &sync.ChartsOptions{SyncPeriod: 60 * time.Second}, logf.Log)
-	applications = sync.NewApplications(k8sClient, helmClient, charts, clusterCache, theClock, &runtimeConfig, gitOpsEngine, logf.Log)
+	applications = sync.NewApplications(k8sClient, helmClient, charts, clusterCache, theClock, &runtimeConfig, peers.NewFakeReachability(), gitOpsEngine, logf.Log)
 
 	jobContext = NewAsyncJobContext(helmClient, k8sClient, ctx, applications)
 })
@@ -14,6 +14,7 @@ import (
 	ctrl_sync "hiro.io/anyapplication/internal/controller/sync"
 	"hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/helm"
+	"hiro.io/anyapplication/internal/peers"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -100,7 +101,7 @@ var _ = Describe("Jobs", func() {
 
 		clusterCache, _ := fixture.NewTestClusterCacheWithOptions([]cache.UpdateSettingsFunc{})
 		fakeCharts := ctrl_sync.NewFakeCharts()
-		applications := ctrl_sync.NewApplications(kubeClient, helmClient, fakeCharts, clusterCache, fakeClock, &runtimeConfig, gitOpsEngine, logf.Log)
+		applications := ctrl_sync.NewApplications(kubeClient, helmClient, fakeCharts, clusterCache, fakeClock, &runtimeConfig, peers.NewFakeReachability(), gitOpsEngine, logf.Log)
 
 		context := NewAsyncJobContext(helmClient, kubeClient, ctx, applications)
 		jobs = NewJobs(context)
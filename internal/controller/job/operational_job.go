@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/cockroachdb/errors"
 	"github.com/go-logr/logr"
 	v1 "hiro.io/anyapplication/api/v1"
 	"hiro.io/anyapplication/internal/clock"
@@ -28,6 +29,8 @@ type LocalOperationJob struct {
 	log           logr.Logger
 	events        *events.Events
 	version       string
+	healthCheck   *healthCheck
+	remediation   *v1.ConditionStatus
 }
 
 func NewLocalOperationJob(
@@ -56,6 +59,8 @@ func NewLocalOperationJob(
 		log:           log,
 		version:       version,
 		events:        events,
+		healthCheck:   newHealthCheck(application, runtimeConfig.ZoneId),
+		remediation:   lastRemediation(application, runtimeConfig.ZoneId),
 	}
 }
 
@@ -79,14 +84,19 @@ func (job *LocalOperationJob) Run(context types.AsyncJobContext) {
 func (job *LocalOperationJob) runInner(context types.AsyncJobContext) bool {
 	applications := context.GetApplications()
 
+	currentVersion, exists := applications.GetTargetVersion(job.application).Get()
+
 	newTargetVersion, err := applications.DetermineTargetVersion(job.application)
-	if err != nil {
+	var unavailableErr *types.VersionUnavailableError
+	switch {
+	case errors.As(err, &unavailableErr) && exists:
+		// the deployed version keeps running, the zone reports the unavailable version separately
+		newTargetVersion = currentVersion
+	case err != nil:
 		job.Fail(context, health.HealthStatusDegraded, "Failed to determine target version: "+err.Error(), "TargetVersionUnavailable")
 		return true
 	}
 
-	currentVersion, exists := applications.GetTargetVersion(job.application).Get()
-
 	if !exists || !newTargetVersion.Equal(currentVersion) {
 		job.Fail(context,
 			health.HealthStatusProgressing,
@@ -96,24 +106,45 @@ func (job *LocalOperationJob) runInner(context types.AsyncJobContext) bool {
 		return true
 	}
 
-	aggregatedStatus := applications.GetAggregatedStatusVersion(job.application, currentVersion)
+	aggregatedStatus := applications.GetAggregatedStatusVersion(context.GetGoContext(), job.application, currentVersion)
 	healthStatus := aggregatedStatus.HealthStatus
 
-	job.status = healthStatus.Status
-	job.msg = healthStatus.Message
-
 	switch healthStatus.Status {
-	case health.HealthStatusHealthy, health.HealthStatusProgressing:
-		job.Success(context, healthStatus.Status)
-		return false
-	case health.HealthStatusDegraded, health.HealthStatusUnknown:
-		job.Fail(context, healthStatus.Status, "", "HealthCheckFailed")
-		return true
+	case health.HealthStatusHealthy, health.HealthStatusProgressing, health.HealthStatusDegraded, health.HealthStatusUnknown:
+		verdict, msg := job.healthCheck.observe(healthStatus.Status, job.clock.NowTime().Time)
+		switch verdict {
+		case healthFail:
+			if job.remediate(context, currentVersion) {
+				return false
+			}
+			reason := HealthCheckFailedReason
+			status := healthStatus.Status
+			if status == health.HealthStatusProgressing {
+				reason = ProgressDeadlineExceededReason
+				status = health.HealthStatusDegraded
+			}
+			job.Fail(context, status, msg, reason)
+			return true
+		case healthHold:
+			job.log.V(1).Info("Health status is within the grace period", "status", healthStatus.Status, "message", healthStatus.Message)
+			return false
+		default:
+			job.Success(context, healthStatus.Status)
+			if healthStatus.Status == health.HealthStatusHealthy {
+				job.recover(context)
+			}
+			return false
+		}
 
 	case health.HealthStatusMissing:
+		if job.remediate(context, currentVersion) {
+			return false
+		}
 		job.Fail(context, health.HealthStatusMissing, "Application resources are missing", "ResourcesMissing")
 		return true
 	default:
+		job.status = healthStatus.Status
+		job.msg = healthStatus.Message
 		return false
 	}
 }
@@ -146,6 +177,9 @@ func (job *LocalOperationJob) updateStatus(jobContext types.AsyncJobContext) {
 		job.events,
 	)
 	event := events.Event{Reason: events.LocalStateChangeReason, Msg: job.msg}
+	if job.reason != "" && job.status != health.HealthStatusProgressing {
+		event = events.Failure(job.reason, job.msg)
+	}
 	err := statusUpdater.UpdateCondition(event, job.GetStatus(), v1.DeploymentConditionType, v1.UndeploymentConditionType)
 	if err != nil {
 		job.log.WithName("StatusUpdater").Error(err, "Failed to update status")
@@ -18,6 +18,7 @@ import (
 	"hiro.io/anyapplication/internal/controller/sync"
 	"hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/helm"
+	"hiro.io/anyapplication/internal/peers"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -143,7 +144,7 @@ var _ = Describe("LocalOperationJobUnitTests", func() {
 
 		clusterCache, _ := fixture.NewTestClusterCacheWithOptions(updateFuncs)
 		charts := sync.NewCharts(context.TODO(), helmClient, &sync.ChartsOptions{SyncPeriod: 60 * time.Second}, logf.Log)
-		applications := sync.NewApplications(kubeClient, helmClient, charts, clusterCache, fakeClock, &runtimeConfig, gitOpsEngine, logf.Log)
+		applications := sync.NewApplications(kubeClient, helmClient, charts, clusterCache, fakeClock, &runtimeConfig, peers.NewFakeReachability(), gitOpsEngine, logf.Log)
 
 		jobContext = NewAsyncJobContext(helmClient, kubeClient, ctx, applications)
 
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/controller/events"
+	"hiro.io/anyapplication/internal/controller/status"
+	"hiro.io/anyapplication/internal/controller/types"
+)
+
+// nextRecoverAction returns the recover action to apply after the last remediation of the zone
+// and its attempt. The actions start over once the application recovered, after the failure
+// cool-down or when a reset of failures is requested.
+func nextRecoverAction(
+	application *v1.AnyApplication,
+	last *v1.ConditionStatus,
+	defaultCooldown time.Duration,
+	now time.Time,
+) (v1.RecoverAction, int, bool) {
+	actions := application.Spec.RecoverStrategy.Actions
+	if len(actions) == 0 {
+		return v1.RecoverAction{}, 0, false
+	}
+	if last == nil || last.Status == string(v1.RemediationStatusRecovered) ||
+		types.RetriesReset(application, last, defaultCooldown, now) {
+		return actions[0], 0, true
+	}
+	if last.Status == string(v1.RemediationStatusExhausted) {
+		return v1.RecoverAction{}, 0, false
+	}
+	_, index, found := lo.FindIndexOf(actions, func(action v1.RecoverAction) bool {
+		return string(action.Type) == last.Reason
+	})
+	if !found {
+		return actions[0], 0, true
+	}
+	if last.RetryAttempt+1 < actions[index].GetLimit() {
+		return actions[index], last.RetryAttempt + 1, true
+	}
+	if index+1 < len(actions) {
+		return actions[index+1], 0, true
+	}
+	return v1.RecoverAction{}, 0, false
+}
+
+func lastRemediation(application *v1.AnyApplication, zoneId string) *v1.ConditionStatus {
+	zoneStatus, found := application.Status.GetStatusFor(zoneId)
+	if !found {
+		return nil
+	}
+	condition, found := zoneStatus.FindCondition(v1.RemediationConditionType)
+	if !found {
+		return nil
+	}
+	return condition.DeepCopy()
+}
+
+// remediate applies the next recover action to the unhealthy application. It tells whether
+// the job keeps polling the health of the application.
+func (job *LocalOperationJob) remediate(context types.AsyncJobContext, version *types.SpecificVersion) bool {
+	now := job.clock.NowTime().Time
+	action, attempt, found := nextRecoverAction(job.application, job.remediation, job.runtimeConfig.FailureCooldown, now)
+	if !found {
+		if job.remediation != nil && job.remediation.Status != string(v1.RemediationStatusExhausted) &&
+			job.remediation.Status != string(v1.RemediationStatusRecovered) {
+			job.recordRemediation(context, job.remediation.Reason, v1.RemediationStatusExhausted, job.remediation.RetryAttempt,
+				"All recover actions are exhausted")
+		}
+		return false
+	}
+
+	applications := context.GetApplications()
+	goContext := context.GetGoContext()
+	var msg string
+	var err error
+	switch action.Type {
+	case v1.RecoverActionResync:
+		var result *types.SyncResult
+		result, err = applications.SyncVersion(goContext, job.application, version)
+		if err == nil {
+			msg = fmt.Sprintf("Synced %d resources of version %s", result.Total, version.ToString())
+		}
+	case v1.RecoverActionRestart:
+		var restarted int
+		restarted, err = applications.RestartWorkloads(goContext, job.application, version)
+		if err == nil {
+			msg = fmt.Sprintf("Restarted %d workloads of version %s", restarted, version.ToString())
+		}
+	case v1.RecoverActionRedeploy:
+		var result *types.DeleteResult
+		result, err = applications.DeleteVersion(goContext, job.application, version)
+		if err == nil {
+			msg = fmt.Sprintf("Deleted %d resources of version %s to deploy it again", result.Deleted, version.ToString())
+		}
+	case v1.RecoverActionRelocate:
+		msg = "Requested relocation to another zone"
+	default:
+		err = fmt.Errorf("unknown recover action %s", action.Type)
+	}
+
+	remediationStatus := v1.RemediationStatusAttempted
+	if err != nil {
+		remediationStatus = v1.RemediationStatusFailed
+		msg = err.Error()
+	}
+	msg = fmt.Sprintf("%s attempt %d of %d: %s", action.Type, attempt+1, action.GetLimit(), msg)
+	job.recordRemediation(context, string(action.Type), remediationStatus, attempt, msg)
+	job.healthCheck.reset()
+
+	inPlace := action.Type == v1.RecoverActionResync || action.Type == v1.RecoverActionRestart
+	return err == nil && inPlace
+}
+
+// recover records that the application is healthy again after its remediation
+func (job *LocalOperationJob) recover(context types.AsyncJobContext) {
+	if job.remediation == nil || job.remediation.Status == string(v1.RemediationStatusRecovered) {
+		return
+	}
+	job.recordRemediation(context, job.remediation.Reason, v1.RemediationStatusRecovered, job.remediation.RetryAttempt,
+		"Application is healthy after "+job.remediation.Reason)
+}
+
+func (job *LocalOperationJob) recordRemediation(
+	jobContext types.AsyncJobContext,
+	action string,
+	remediationStatus v1.RemediationStatus,
+	attempt int,
+	msg string,
+) {
+	condition := v1.ConditionStatus{
+		Type:               v1.RemediationConditionType,
+		ZoneId:             job.runtimeConfig.ZoneId,
+		Status:             string(remediationStatus),
+		LastTransitionTime: job.clock.NowTime(),
+		Reason:             action,
+		Msg:                msg,
+		RetryAttempt:       attempt,
+	}
+	job.remediation = &condition
+
+	statusUpdater := status.NewStatusUpdater(
+		jobContext.GetGoContext(),
+		job.log.WithName("StatusUpdater"),
+		jobContext.GetKubeClient(),
+		job.application.GetNamespacedName(),
+		job.runtimeConfig.ZoneId,
+		job.events,
+	)
+	event := events.Event{Reason: events.RemediationReason, Msg: msg}
+	switch remediationStatus {
+	case v1.RemediationStatusFailed:
+		event = events.Failure(events.RemediationFailedReason, msg)
+	case v1.RemediationStatusExhausted:
+		event = events.Failure(events.RemediationExhaustedReason, msg)
+	}
+	if err := statusUpdater.UpdateCondition(event, condition); err != nil {
+		job.log.WithName("StatusUpdater").Error(err, "Failed to update remediation status")
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "hiro.io/anyapplication/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Recover actions", func() {
+	var (
+		application *v1.AnyApplication
+		now         time.Time
+	)
+
+	remediation := func(action v1.RecoverActionType, status v1.RemediationStatus, attempt int) *v1.ConditionStatus {
+		return &v1.ConditionStatus{
+			Type:               v1.RemediationConditionType,
+			ZoneId:             "zone",
+			Status:             string(status),
+			Reason:             string(action),
+			RetryAttempt:       attempt,
+			LastTransitionTime: metav1.NewTime(now.Add(-time.Minute)),
+		}
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		application = &v1.AnyApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+			Spec: v1.AnyApplicationSpec{
+				RecoverStrategy: v1.RecoverStrategySpec{
+					Actions: []v1.RecoverAction{
+						{Type: v1.RecoverActionResync},
+						{Type: v1.RecoverActionRestart, Limit: 2},
+						{Type: v1.RecoverActionRelocate},
+					},
+				},
+			},
+		}
+	})
+
+	It("should not remediate without actions", func() {
+		application.Spec.RecoverStrategy.Actions = nil
+
+		_, _, found := nextRecoverAction(application, nil, 0, now)
+		Expect(found).To(BeFalse())
+	})
+
+	It("should start with the first action", func() {
+		action, attempt, found := nextRecoverAction(application, nil, 0, now)
+		Expect(found).To(BeTrue())
+		Expect(action.Type).To(Equal(v1.RecoverActionResync))
+		Expect(attempt).To(Equal(0))
+	})
+
+	It("should repeat an action up to its limit before the next action", func() {
+		action, attempt, _ := nextRecoverAction(application, remediation(v1.RecoverActionResync, v1.RemediationStatusAttempted, 0), 0, now)
+		Expect(action.Type).To(Equal(v1.RecoverActionRestart))
+		Expect(attempt).To(Equal(0))
+
+		action, attempt, _ = nextRecoverAction(application, remediation(v1.RecoverActionRestart, v1.RemediationStatusFailed, 0), 0, now)
+		Expect(action.Type).To(Equal(v1.RecoverActionRestart))
+		Expect(attempt).To(Equal(1))
+
+		action, attempt, _ = nextRecoverAction(application, remediation(v1.RecoverActionRestart, v1.RemediationStatusAttempted, 1), 0, now)
+		Expect(action.Type).To(Equal(v1.RecoverActionRelocate))
+		Expect(attempt).To(Equal(0))
+	})
+
+	It("should stop once all actions are exhausted", func() {
+		_, _, found := nextRecoverAction(application, remediation(v1.RecoverActionRelocate, v1.RemediationStatusAttempted, 0), 0, now)
+		Expect(found).To(BeFalse())
+
+		_, _, found = nextRecoverAction(application, remediation(v1.RecoverActionRelocate, v1.RemediationStatusExhausted, 0), 0, now)
+		Expect(found).To(BeFalse())
+	})
+
+	It("should start over once recovered or after the cool-down", func() {
+		action, _, _ := nextRecoverAction(application, remediation(v1.RecoverActionRelocate, v1.RemediationStatusRecovered, 0), 0, now)
+		Expect(action.Type).To(Equal(v1.RecoverActionResync))
+
+		action, _, _ = nextRecoverAction(application, remediation(v1.RecoverActionRelocate, v1.RemediationStatusExhausted, 0), time.Minute, now)
+		Expect(action.Type).To(Equal(v1.RecoverActionResync))
+	})
+})
@@ -30,6 +30,7 @@ type UndeployJob struct {
 	startTime     time.Time
 	retryAttempts int
 	attempt       int
+	retryAttempt  int
 }
 
 func NewUndeployJob(
@@ -48,6 +49,8 @@ func NewUndeployJob(
 	}
 	version := application.ResourceVersion
 	log = log.WithName("UndeployJob")
+	retryAttempt := types.NextRetryAttempt(application, runtimeConfig.ZoneId, v1.UndeploymentConditionType,
+		string(v1.UndeploymentStatusFailure), runtimeConfig.FailureCooldown, clock.NowTime().Time)
 	return &UndeployJob{
 		status:        v1.UndeploymentStatusUndeploy,
 		application:   application,
@@ -60,6 +63,7 @@ func NewUndeployJob(
 		events:        events,
 		retryAttempts: 3,
 		attempt:       1,
+		retryAttempt:  retryAttempt,
 		startTime:     clock.NowTime().Time,
 	}
 }
@@ -194,6 +198,12 @@ func (job *UndeployJob) updateStatus(jobContext types.AsyncJobContext) {
 		job.events,
 	)
 	event := events.Event{Reason: events.LocalStateChangeReason, Msg: job.msg}
+	switch {
+	case job.reason == "Timeout":
+		event = events.Failure(events.UndeploymentTimeoutReason, job.msg)
+	case job.reason != "":
+		event = events.Failure(events.UndeploymentFailedReason, job.msg)
+	}
 	err := statusUpdater.UpdateCondition(event, job.GetStatus(), v1.LocalConditionType, v1.DeploymentConditionType)
 	if err != nil {
 		job.log.WithName("StatusUpdater").Error(err, "Failed to update status")
@@ -216,5 +226,6 @@ func (job *UndeployJob) GetStatus() v1.ConditionStatus {
 		LastTransitionTime: job.clock.NowTime(),
 		Msg:                job.msg,
 		Reason:             job.reason,
+		RetryAttempt:       job.retryAttempt,
 	}
 }
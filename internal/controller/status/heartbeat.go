@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
+	"hiro.io/anyapplication/internal/controller/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Heartbeat periodically refreshes the heartbeat time of the zone status of all applications the
+// zone reports a status for. The owner zone relocates placements whose heartbeat becomes stale.
+type Heartbeat struct {
+	client   client.Client
+	zoneId   string
+	interval time.Duration
+	clock    clock.Clock
+	events   *events.Events
+	log      logr.Logger
+}
+
+func NewHeartbeat(
+	client client.Client,
+	zoneId string,
+	interval time.Duration,
+	clock clock.Clock,
+	events *events.Events,
+	log logr.Logger,
+) *Heartbeat {
+	return &Heartbeat{
+		client:   client,
+		zoneId:   zoneId,
+		interval: interval,
+		clock:    clock,
+		events:   events,
+		log:      log.WithName("Heartbeat"),
+	}
+}
+
+// Run beats until the context is done, heartbeats are disabled when the interval is not positive
+func (h *Heartbeat) Run(ctx context.Context) {
+	if h.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := h.Beat(ctx); err != nil {
+				h.log.Error(err, "Failed to refresh heartbeats")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Beat refreshes the heartbeat of every zone status of the zone which is older than the interval
+func (h *Heartbeat) Beat(ctx context.Context) error {
+	applications := &v1.AnyApplicationList{}
+	if err := h.client.List(ctx, applications); err != nil {
+		return err
+	}
+	for _, application := range applications.Items {
+		if !h.isDue(&application) {
+			continue
+		}
+		statusUpdater := NewStatusUpdater(ctx, h.log, h.client, application.GetNamespacedName(), h.zoneId, h.events)
+		err := statusUpdater.UpdateStatus(func(status *v1.AnyApplicationStatus, zoneId string) (bool, events.Event) {
+			zoneStatus, found := status.GetStatusFor(zoneId)
+			if !found {
+				return false, events.Event{}
+			}
+			now := h.clock.NowTime()
+			zoneStatus.LastHeartbeatTime = &now
+			return true, events.Event{}
+		})
+		if err != nil {
+			h.log.Error(err, "Failed to refresh heartbeat", "name", application.Name, "namespace", application.Namespace)
+		}
+	}
+	return nil
+}
+
+func (h *Heartbeat) isDue(application *v1.AnyApplication) bool {
+	zoneStatus, found := application.Status.GetStatusFor(h.zoneId)
+	if !found {
+		return false
+	}
+	if zoneStatus.LastHeartbeatTime == nil {
+		return true
+	}
+	// refresh slightly early so that the heartbeat does not skip a tick
+	return h.clock.NowTime().Sub(zoneStatus.LastHeartbeatTime.Time) >= h.interval/2
+}
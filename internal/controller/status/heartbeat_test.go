@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package status
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
+	"hiro.io/anyapplication/internal/controller/events"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Heartbeat", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		fakeClock  *clock.FakeClock
+		fakeEvents events.Events
+		heartbeat  *Heartbeat
+	)
+
+	newApplication := func(name string, zones ...v1.ZoneStatus) *v1.AnyApplication {
+		return &v1.AnyApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1.AnyApplicationSpec{
+				PlacementStrategy: v1.PlacementStrategySpec{Strategy: v1.PlacementStrategyLocal},
+			},
+			Status: v1.AnyApplicationStatus{
+				Ownership: v1.OwnershipStatus{Epoch: 1, Owner: "zone", State: v1.OperationalGlobalState},
+				Zones:     zones,
+			},
+		}
+	}
+
+	getZoneStatus := func(name string, zoneId string) *v1.ZoneStatus {
+		application := &v1.AnyApplication{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, application)).To(Succeed())
+		zoneStatus, found := application.Status.GetStatusFor(zoneId)
+		Expect(found).To(BeTrue())
+		return zoneStatus
+	}
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		fakeClock = clock.NewFakeClock()
+		fakeClock.SetNow(60 * 60 * 1000)
+		fakeEvents = events.NewFakeEvents()
+		scheme := runtime.NewScheme()
+		_ = v1.AddToScheme(scheme)
+
+		recent := metav1.NewTime(fakeClock.NowTime().Add(-5 * time.Second))
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(
+				newApplication("reporting", v1.ZoneStatus{ZoneId: "zone"}, v1.ZoneStatus{ZoneId: "other"}),
+				newApplication("recent", v1.ZoneStatus{ZoneId: "zone", LastHeartbeatTime: &recent}),
+				newApplication("foreign", v1.ZoneStatus{ZoneId: "other"}),
+			).
+			WithStatusSubresource(&v1.AnyApplication{}).
+			Build()
+		heartbeat = NewHeartbeat(fakeClient, "zone", time.Minute, fakeClock, &fakeEvents, logr.Discard())
+	})
+
+	It("should refresh heartbeats of the own zone status only", func() {
+		Expect(heartbeat.Beat(ctx)).To(Succeed())
+
+		now := fakeClock.NowTime()
+		Expect(getZoneStatus("reporting", "zone").LastHeartbeatTime.Unix()).To(Equal(now.Unix()))
+		Expect(getZoneStatus("reporting", "other").LastHeartbeatTime).To(BeNil())
+		Expect(getZoneStatus("foreign", "other").LastHeartbeatTime).To(BeNil())
+
+		application := &v1.AnyApplication{}
+		Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "foreign", Namespace: "default"}, application)).To(Succeed())
+		Expect(application.Status.ZoneExists("zone")).To(BeFalse())
+	})
+
+	It("should refresh heartbeats once half of the interval passed", func() {
+		Expect(heartbeat.Beat(ctx)).To(Succeed())
+		Expect(getZoneStatus("recent", "zone").LastHeartbeatTime.Time).To(BeTemporally("<", fakeClock.NowTime().Time))
+
+		fakeClock.Advance(30 * time.Second)
+		Expect(heartbeat.Beat(ctx)).To(Succeed())
+		Expect(getZoneStatus("recent", "zone").LastHeartbeatTime.Unix()).To(Equal(fakeClock.NowTime().Unix()))
+	})
+})
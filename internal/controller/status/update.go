@@ -70,7 +70,9 @@ func (su *StatusUpdater) UpdateStatus(
 
 			err := su.client.Status().Update(su.ctx, updatedApplication)
 			if err == nil {
-				su.events.Emit(updatedApplication, eventToSend)
+				if eventToSend.Reason != "" {
+					su.events.Emit(updatedApplication, eventToSend)
+				}
 				updatedApplication.Status.LogStatus()
 				su.log.Info("Updating status", "status", updatedApplication.Status, "error", err)
 			}
@@ -9,7 +9,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"sort"
-	"sync"
 
 	"github.com/argoproj/gitops-engine/pkg/cache"
 	"github.com/argoproj/gitops-engine/pkg/engine"
@@ -28,6 +27,7 @@ import (
 	"hiro.io/anyapplication/internal/controller/local"
 	"hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/helm"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -39,9 +39,12 @@ type applications struct {
 	charts       types.Charts
 	kubeClient   client.Client
 	clusterCache cache.ClusterCache
-	appCache     sync.Map
+	renderCache  *renderCache
+	renderStore  *renderStore
+	keyrings     *keyrings
 	clock        clock.Clock
 	config       *config.ApplicationRuntimeConfig
+	peers        types.ZoneReachability
 	gitOpsEngine engine.GitOpsEngine
 	log          logr.Logger
 }
@@ -53,6 +56,7 @@ func NewApplications(
 	clusterCache cache.ClusterCache,
 	clock clock.Clock,
 	config *config.ApplicationRuntimeConfig,
+	peers types.ZoneReachability,
 	gitOpsEngine engine.GitOpsEngine,
 	logger logr.Logger,
 ) types.Applications {
@@ -63,9 +67,12 @@ func NewApplications(
 		charts:       charts,
 		helmClient:   helmClient,
 		clusterCache: clusterCache,
-		appCache:     sync.Map{},
+		renderCache:  newRenderCache(config.RenderCacheSize),
+		renderStore:  newRenderStore(config.RenderCacheDir, log),
+		keyrings:     newKeyrings(kubeClient, config.ChartVerification),
 		clock:        clock,
 		config:       config,
+		peers:        peers,
 		gitOpsEngine: gitOpsEngine,
 		log:          log,
 	}
@@ -86,9 +93,27 @@ func (m *applications) GetTargetVersion(
 	return mo.Some(version)
 }
 
+// DetermineTargetVersion returns the chart version the zone deploys. Zones deploy the version
+// published by the owner, or the version they are admitted to during a rollout. The version
+// range is resolved locally only as long as the owner has not published a version.
 func (m *applications) DetermineTargetVersion(
 	application *v1.AnyApplication,
 ) (*types.SpecificVersion, error) {
+	ownership := &application.Status.Ownership
+	version := ownership.TargetVersion
+	if ownership.Rollout != nil {
+		version = ownership.Rollout.TargetVersion(m.config.ZoneId)
+	}
+	if version == "" {
+		return m.resolveVersion(application)
+	}
+	return m.fetchVersion(application, version)
+}
+
+// resolveVersion resolves the version range of the source with the local charts
+func (m *applications) resolveVersion(
+	application *v1.AnyApplication,
+) (*types.SpecificVersion, error) {
 
 	helmSource := application.Spec.Source.HelmSelector
 	chartVersion, err := types.NewChartVersion(helmSource.Version)
@@ -96,10 +121,53 @@ func (m *applications) DetermineTargetVersion(
 		return nil, err
 	}
 
-	if chartKey, err := m.charts.AddAndGetLatest(helmSource.Chart, helmSource.Repository, chartVersion); err == nil {
-		return &chartKey.Version, nil
+	chartKey, err := m.charts.AddAndGetLatest(helmSource.Chart, helmSource.Repository, chartVersion)
+	if err != nil {
+		return nil, err
 	}
-	return nil, err
+	return &chartKey.Version, nil
+}
+
+// resolveOwnerVersion resolves the version range for the owner. The owner keeps the published
+// version while the chart repository is not available, e.g. when it restarts offline.
+func (m *applications) resolveOwnerVersion(
+	application *v1.AnyApplication,
+) (*types.SpecificVersion, error) {
+	version, err := m.resolveVersion(application)
+	published := application.Status.Ownership.TargetVersion
+	if err == nil || published == "" {
+		return version, err
+	}
+	publishedVersion, parseErr := types.NewSpecificVersion(published)
+	if parseErr != nil {
+		return nil, err
+	}
+	m.log.Error(err, "Chart repository is not available, keeping the published version",
+		"application", application.GetNamespacedName(), "version", published)
+	return publishedVersion, nil
+}
+
+// fetchVersion makes sure the exact chart version is available in the zone
+func (m *applications) fetchVersion(
+	application *v1.AnyApplication,
+	version string,
+) (*types.SpecificVersion, error) {
+	specificVersion, err := types.NewSpecificVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	helmSource := application.Spec.Source.HelmSelector
+	chartKey, err := m.charts.AddAndGetLatest(helmSource.Chart, helmSource.Repository, specificVersion)
+	if err != nil {
+		// the deployed version is assessed from the render cache without the chart repository
+		if deployed, found := m.GetTargetVersion(application).Get(); found && deployed.Equal(specificVersion) {
+			m.log.Error(err, "Chart repository is not available, keeping the deployed version",
+				"application", application.GetNamespacedName(), "version", version)
+			return specificVersion, nil
+		}
+		return nil, &types.VersionUnavailableError{Version: version, Err: err}
+	}
+	return &chartKey.Version, nil
 }
 
 func (m *applications) SyncVersion(
@@ -107,7 +175,7 @@ func (m *applications) SyncVersion(
 	application *v1.AnyApplication,
 	version *types.SpecificVersion,
 ) (*types.SyncResult, error) {
-	app, err := m.getOrRenderAppVersion(application, version)
+	app, err := m.getOrRenderAppVersion(ctx, application, version)
 	if err != nil {
 		return types.NewSyncResult(), err
 	}
@@ -115,52 +183,102 @@ func (m *applications) SyncVersion(
 }
 
 func (m *applications) getOrRenderAppVersion(
+	ctx context.Context,
+	application *v1.AnyApplication,
+	version *types.SpecificVersion,
+) (*cachedApp, error) {
+	keyring, err := m.keyrings.Resolve(ctx, application)
+	if err != nil {
+		return nil, err
+	}
+	return m.getOrRenderWithKeyring(application, version, keyring)
+}
+
+// getOrRenderWithKeyring looks the version up in the render cache with the already resolved keyring
+func (m *applications) getOrRenderWithKeyring(
 	application *v1.AnyApplication,
 	version *types.SpecificVersion,
+	keyring *helm.Keyring,
 ) (*cachedApp, error) {
 	appKey := m.getApplicationKey(application)
 	chartKey := types.ChartKey{
 		ChartId: types.NewChartId(application),
 		Version: *version,
 	}
+	uniqueConfiguration := m.buildInstanceKey(application, &chartKey, keyring)
 
-	instances := m.getOrCreateInstances(appKey)
-	uniqueConfiguration := m.buildInstanceKey(application, &chartKey)
-
-	cachedApp, exists := instances.Get(&uniqueConfiguration)
+	cachedApp, exists := m.renderCache.Get(appKey, &uniqueConfiguration)
 	if !exists {
-		newApp, err := m.render(application, &uniqueConfiguration)
+		newApp, err := m.loadOrRender(application, appKey, &uniqueConfiguration)
 		if err != nil {
 			return nil, err
 		}
-		instances.Put(&uniqueConfiguration, newApp)
+		m.renderCache.Put(appKey, &uniqueConfiguration, newApp)
 		cachedApp = newApp
 	}
 	return cachedApp, nil
 }
 
-func (m *applications) getOrCreateInstances(appKey string) *cachedInstances {
-	instances, exists := m.appCache.Load(appKey)
-	if !exists {
-		instances = NewCachedInstances()
-		m.appCache.Store(appKey, instances)
+// loadOrRender reads the rendered instance from the on-disk cache
+// and falls back to rendering the chart, persisting the result.
+func (m *applications) loadOrRender(application *v1.AnyApplication, appKey string, configuration *instanceKey) (*cachedApp, error) {
+	revision, err := configuration.Revision()
+	if err != nil {
+		return nil, err
 	}
-	return instances.(*cachedInstances)
+	if resources, found := m.renderStore.Load(appKey, revision); found {
+		return &cachedApp{
+			application: application.DeepCopy(),
+			chartKey:    configuration.ChartKey,
+			instance:    configuration.Instance,
+			revision:    revision,
+			renderedChart: &types.RenderedChart{
+				Key:       *configuration.ChartKey,
+				Instance:  *configuration.Instance,
+				Resources: resources,
+			},
+		}, nil
+	}
+
+	app, err := m.render(application, configuration)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.renderStore.Store(appKey, revision, app.renderedChart.Resources); err != nil {
+		m.log.Error(err, "Failed to persist rendered chart", "application", application.GetNamespacedName())
+	}
+	return app, nil
 }
 
-func (m *applications) buildInstanceKey(application *v1.AnyApplication, chartKey *types.ChartKey) instanceKey {
+func (m *applications) buildInstanceKey(
+	application *v1.AnyApplication,
+	chartKey *types.ChartKey,
+	keyring *helm.Keyring,
+) instanceKey {
 	return instanceKey{
 		ChartKey: chartKey,
 		Instance: &types.ApplicationInstance{
-			InstanceId:  m.GetInstanceId(application),
-			Name:        application.Name,
-			Namespace:   application.Namespace,
-			ReleaseName: application.Name,
-			ValuesYaml:  application.Spec.Source.HelmSelector.Values,
+			InstanceId:   m.GetInstanceId(application),
+			Name:         application.Name,
+			Namespace:    application.Namespace,
+			ReleaseName:  releaseName(application, &chartKey.Version),
+			ValuesYaml:   application.Spec.Source.HelmSelector.Values,
+			Keyring:      keyring,
+			NodeAffinity: m.getNodeAffinity(application),
 		},
 	}
 }
 
+func (m *applications) getNodeAffinity(application *v1.AnyApplication) []string {
+	placement, found := lo.Find(application.Status.Ownership.Placements, func(placement v1.Placement) bool {
+		return placement.Zone == m.config.ZoneId
+	})
+	if !found {
+		return nil
+	}
+	return placement.NodeAffinity
+}
+
 func (m *applications) render(application *v1.AnyApplication, configuration *instanceKey) (*cachedApp, error) {
 
 	renderedChart, err := m.charts.Render(configuration.ChartKey, configuration.Instance)
@@ -188,10 +306,15 @@ func (m *applications) sync(ctx context.Context, app *cachedApp) (*types.SyncRes
 	syncResult := types.NewSyncResult()
 	prune := true
 
+	isManaged := m.isManagedFunc(app.instance.InstanceId)
+	if app.application.Spec.UpgradeStrategy.IsBlueGreen() {
+		isManaged = m.isManagedVersionFunc(app)
+	}
+
 	resourceSyncResults, err := m.gitOpsEngine.Sync(
 		ctx,
-		app.renderedChart.Resources,
-		m.isManagedFunc(app.instance.InstanceId),
+		m.targetResources(app),
+		isManaged,
 		app.revision,
 		app.instance.Namespace,
 		gitops_sync.WithPrune(prune),
@@ -206,7 +329,9 @@ func (m *applications) sync(ctx context.Context, app *cachedApp) (*types.SyncRes
 
 	syncResult.AggregatedStatus = m.getAggregatedStatus(app)
 
-	localApplications, err := m.loadLocalApplicationVersions(app.application)
+	// the instance was rendered with the keyring just resolved for the application
+	instanceKeyring := func() (*helm.Keyring, error) { return app.instance.Keyring, nil }
+	localApplications, err := m.loadLocalApplicationVersions(app.application, instanceKeyring)
 	localApplication, exists := localApplications[app.chartKey.Version]
 	if err == nil {
 		syncResult.ApplicationResourcesPresent = exists
@@ -246,12 +371,12 @@ func (m *applications) isManagedFunc(instanceId string) IsManagedResourceFunc {
 	}
 }
 
-func (m *applications) GetRenderedChart(application *v1.AnyApplication) (*types.RenderedChart, error) {
-	version, err := m.DetermineTargetVersion(application)
-	if err != nil {
-		return nil, err
-	}
-	cachedApp, err := m.getOrRenderAppVersion(application, version)
+func (m *applications) GetRenderedChart(
+	ctx context.Context,
+	application *v1.AnyApplication,
+	version *types.SpecificVersion,
+) (*types.RenderedChart, error) {
+	cachedApp, err := m.getOrRenderAppVersion(ctx, application, version)
 	if err != nil {
 		return nil, err
 	}
@@ -259,10 +384,11 @@ func (m *applications) GetRenderedChart(application *v1.AnyApplication) (*types.
 }
 
 func (m *applications) GetAggregatedStatusVersion(
+	ctx context.Context,
 	application *v1.AnyApplication,
 	version *types.SpecificVersion,
 ) *types.AggregatedStatus {
-	app, err := m.getOrRenderAppVersion(application, version)
+	app, err := m.getOrRenderAppVersion(ctx, application, version)
 	if err != nil {
 		m.log.Error(err, "Failed to get or render application")
 	}
@@ -312,7 +438,7 @@ func (m *applications) DeleteVersion(
 	application *v1.AnyApplication,
 	version *types.SpecificVersion,
 ) (*types.DeleteResult, error) {
-	app, err := m.getOrRenderAppVersion(application, version)
+	app, err := m.getOrRenderAppVersion(ctx, application, version)
 	if err != nil {
 		return nil, err
 	}
@@ -320,6 +446,44 @@ func (m *applications) DeleteVersion(
 	return m.deleteApp(ctx, app)
 }
 
+// PruneVersions deletes resources left by other versions than the given one
+// which are not part of the given version anymore.
+func (m *applications) PruneVersions(
+	ctx context.Context,
+	application *v1.AnyApplication,
+	version *types.SpecificVersion,
+) (*types.DeleteResult, error) {
+	app, err := m.getOrRenderAppVersion(ctx, application, version)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedKeys := mapset.NewSet[kube.ResourceKey]()
+	for _, obj := range app.renderedChart.Resources {
+		expectedKeys.Add(kube.GetResourceKey(obj))
+	}
+
+	deleteResult := &types.DeleteResult{Version: version}
+	for _, obj := range m.findAvailableApplicationResources(application) {
+		if obj.GetLabels()[LABEL_CHART_VERSION] == version.ToString() || expectedKeys.Contains(kube.GetResourceKey(obj)) {
+			continue
+		}
+		fullName := getFullName(obj)
+		deleteResult.Total += 1
+		m.log.V(1).Info("Pruning resource of previous version", "Resource", fullName)
+
+		err := m.kubeClient.Delete(ctx, obj)
+		if err != nil && !apierrors.IsNotFound(err) {
+			deleteResult.DeleteFailed += 1
+			m.log.Error(err, "Failed to prune resource", "Resource", fullName)
+		} else {
+			deleteResult.Deleted += 1
+		}
+	}
+	deleteResult.ApplicationResourcesPresent = deleteResult.DeleteFailed > 0
+	return deleteResult, nil
+}
+
 func (m *applications) deleteApp(ctx context.Context, app *cachedApp) (*types.DeleteResult, error) {
 	deleteResult := &types.DeleteResult{}
 
@@ -384,38 +548,123 @@ func (m *applications) GetInstanceId(application *v1.AnyApplication) string {
 	return fmt.Sprintf("%s-%s", appNamespace, appName)
 }
 
-func (m *applications) LoadApplication(application *v1.AnyApplication) (types.GlobalApplication, error) {
-	localApplications, err := m.loadLocalApplicationVersions(application)
+func (m *applications) LoadApplication(ctx context.Context, application *v1.AnyApplication) (types.GlobalApplication, error) {
+	keyring := m.keyrings.resolveOnce(ctx, application)
+	localApplications, err := m.loadLocalApplicationVersions(application, keyring)
 	if err != nil {
 		return nil, errors.Wrap(err, "Fail to create local application")
 	}
 
+	resolvedVersion := mo.None[*types.SpecificVersion]()
+	if application.Status.Ownership.Owner == m.config.ZoneId {
+		version, err := m.resolveOwnerVersion(application)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to resolve target version")
+		}
+		resolvedVersion = mo.Some(version)
+	}
+
 	newVersion := mo.None[*types.SpecificVersion]()
+	unavailableVersion := mo.None[*types.VersionUnavailableError]()
 
 	activeVersionOpt := m.GetTargetVersion(application)
 	targetVersion, err := m.DetermineTargetVersion(application)
-	if err != nil {
+	var unavailableErr *types.VersionUnavailableError
+	switch {
+	case errors.As(err, &unavailableErr):
+		// the zone keeps the deployed version until it can fetch the published one
+		m.log.Error(err, "Published version is not available", "application", application.GetNamespacedName())
+		unavailableVersion = mo.Some(unavailableErr)
+		targetVersion = activeVersionOpt.OrEmpty()
+	case err != nil:
 		return nil, errors.Wrap(err, "Failed to determine target version")
 	}
-	activeVersion, exists := activeVersionOpt.Get()
-	if !exists || !targetVersion.Equal(activeVersion) {
-		newVersion = mo.Some(targetVersion)
+
+	if targetVersion != nil {
+		activeVersion, exists := activeVersionOpt.Get()
+		if !exists || !targetVersion.Equal(activeVersion) {
+			newVersion = mo.Some(targetVersion)
+		}
+		m.evictStaleInstances(application, keyring, activeVersionOpt, targetVersion, localApplications)
 	}
 
 	globalApplication := global.NewFromLocalApplication(
 		localApplications,
 		activeVersionOpt,
 		newVersion,
+		resolvedVersion,
+		unavailableVersion,
 		m.clock,
 		application,
 		m.config,
+		m.peers,
 		m.log,
 	)
 	return globalApplication, nil
 }
 
+// Forget drops all rendered instances of the application from memory.
+// It is called once the application has been deleted.
+func (m *applications) Forget(application *v1.AnyApplication) {
+	appKey := m.getApplicationKey(application)
+	evicted := m.renderCache.RemoveApp(appKey)
+	if err := m.renderStore.RemoveApp(appKey); err != nil {
+		m.log.Error(err, "Failed to drop persisted rendered instances", "application", application.GetNamespacedName())
+	}
+	m.log.V(1).Info("Dropped rendered instances of deleted application",
+		"application", application.GetNamespacedName(), "evicted", evicted)
+}
+
+// evictStaleInstances keeps only instances rendered with the current configuration
+// for the active, target and still present versions of the application.
+func (m *applications) evictStaleInstances(
+	application *v1.AnyApplication,
+	resolveKeyring keyringResolver,
+	activeVersion mo.Option[*types.SpecificVersion],
+	targetVersion *types.SpecificVersion,
+	localApplications map[types.SpecificVersion]*local.LocalApplication,
+) {
+	versionsToKeep := mapset.NewSet(*targetVersion)
+	if version, ok := activeVersion.Get(); ok {
+		versionsToKeep.Add(*version)
+	}
+	for version := range localApplications {
+		versionsToKeep.Add(version)
+	}
+	keyring, err := resolveKeyring()
+	if err != nil {
+		m.log.Error(err, "Skipping eviction of rendered instances", "application", application.GetNamespacedName())
+		return
+	}
+	appKey := m.getApplicationKey(application)
+
+	evicted := m.renderCache.Retain(appKey, func(app *cachedApp) bool {
+		currentInstance := m.buildInstanceKey(application, app.chartKey, keyring).Instance.ToString()
+		return versionsToKeep.Contains(app.chartKey.Version) && app.instance.ToString() == currentInstance
+	})
+
+	revisionsToKeep := mapset.NewSet[string]()
+	for version := range versionsToKeep.Iter() {
+		chartKey := types.ChartKey{ChartId: types.NewChartId(application), Version: version}
+		key := m.buildInstanceKey(application, &chartKey, keyring)
+		if revision, err := key.Revision(); err == nil {
+			revisionsToKeep.Add(revision)
+		}
+	}
+	removed, err := m.renderStore.Retain(appKey, revisionsToKeep)
+	if err != nil {
+		m.log.Error(err, "Failed to evict persisted rendered instances", "application", application.GetNamespacedName())
+	}
+
+	if evicted > 0 || removed > 0 {
+		m.log.V(1).Info("Evicted stale rendered instances",
+			"application", application.GetNamespacedName(), "evicted", evicted, "removed", removed)
+	}
+}
+
 func (m *applications) loadLocalApplicationVersions(
 	application *v1.AnyApplication,
+	resolveKeyring keyringResolver,
 ) (map[types.SpecificVersion]*local.LocalApplication, error) {
 
 	availableResources := m.findAvailableApplicationResources(application)
@@ -434,11 +683,15 @@ func (m *applications) loadLocalApplicationVersions(
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed to parse version string %s", versionStr)
 		}
-		cachedApp, err := m.getOrRenderAppVersion(application, version)
+		keyring, err := resolveKeyring()
+		if err != nil {
+			return nil, err
+		}
+		cachedApp, err := m.getOrRenderWithKeyring(application, version, keyring)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed to get or render application for version %s", versionStr)
 		}
-		expectedResources := cachedApp.renderedChart.Resources
+		expectedResources := m.targetResources(cachedApp)
 
 		localApplication, err := local.NewFromUnstructured(version, resources, expectedResources, m.config, m.clock, m.log)
 		if err != nil {
@@ -500,47 +753,6 @@ func getFullName(obj *unstructured.Unstructured) string {
 	return fmt.Sprintf("%s/%s (%s)", namespace, name, gvk.Kind)
 }
 
-type cachedInstances struct {
-	// map[str(uniqueConfiguration)]*cachedApp
-	configurations sync.Map
-}
-
-func NewCachedInstances() *cachedInstances {
-	return &cachedInstances{
-		configurations: sync.Map{},
-	}
-}
-
-func (m *cachedInstances) Contains(config *instanceKey) bool {
-	_, ok := m.configurations.Load(config.ToString())
-	return ok
-}
-
-func (c *cachedInstances) Put(
-	config *instanceKey,
-	app *cachedApp,
-) {
-	c.configurations.Store(config.ToString(), app)
-}
-
-func (c *cachedInstances) Get(
-	key *instanceKey,
-) (*cachedApp, bool) {
-	if app, ok := c.configurations.Load(key.ToString()); ok {
-		return app.(*cachedApp), true
-	}
-	return nil, false
-}
-
-func (c *cachedInstances) IsEmpty() bool {
-	empty := true
-	c.configurations.Range(func(_, _ any) bool {
-		empty = false
-		return false // stop iteration
-	})
-	return empty
-}
-
 type cachedApp struct {
 	application   *v1.AnyApplication
 	chartKey      *types.ChartKey
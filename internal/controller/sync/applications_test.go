@@ -21,6 +21,7 @@ import (
 	"hiro.io/anyapplication/internal/controller/fixture"
 	"hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/helm"
+	"hiro.io/anyapplication/internal/peers"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -142,7 +143,7 @@ var _ = Describe("Applications", func() {
 		gitOpsEngine = fixture.NewFakeGitopsEngine()
 		charts = NewCharts(context.TODO(), helmClient, &ChartsOptions{SyncPeriod: 60 * time.Second}, logf.Log)
 		applications = NewApplications(kubeClient, helmClient, charts,
-			clusterCache, fakeClock, &runtimeConfig, gitOpsEngine, logf.Log)
+			clusterCache, fakeClock, &runtimeConfig, peers.NewFakeReachability(), gitOpsEngine, logf.Log)
 	})
 
 	It("should get target version for the application", func() {
@@ -170,7 +171,7 @@ var _ = Describe("Applications", func() {
 		}
 
 		charts = NewCharts(context.TODO(), helmClient, &ChartsOptions{SyncPeriod: 60 * time.Second}, logf.Log)
-		applications = NewApplications(kubeClient, helmClient, charts, clusterCache, fakeClock, &runtimeConfig, gitOpsEngine, logf.Log)
+		applications = NewApplications(kubeClient, helmClient, charts, clusterCache, fakeClock, &runtimeConfig, peers.NewFakeReachability(), gitOpsEngine, logf.Log)
 
 		version201, _ := types.NewSpecificVersion("2.0.1")
 		_, err := applications.SyncVersion(context.Background(), application, version201)
@@ -183,11 +184,11 @@ var _ = Describe("Applications", func() {
 		versions, _ := applications.GetAllPresentVersions(application)
 		Expect(versions.ToSlice()).To(HaveLen(2))
 
-		status := applications.GetAggregatedStatusVersion(application, version200)
+		status := applications.GetAggregatedStatusVersion(context.Background(), application, version200)
 		Expect(status.HealthStatus.Status).To(Equal(health.HealthStatusMissing))
 		Expect(status.ChartVersion.ToString()).To(Equal("2.0.0"))
 
-		status = applications.GetAggregatedStatusVersion(application, version201)
+		status = applications.GetAggregatedStatusVersion(context.Background(), application, version201)
 		Expect(status.HealthStatus.Status).To(Equal(health.HealthStatusMissing))
 		Expect(status.ChartVersion.ToString()).To(Equal("2.0.1"))
 
@@ -221,7 +222,7 @@ var _ = Describe("Applications", func() {
 			Build()
 
 		charts = NewCharts(context.Background(), helmClient, &ChartsOptions{SyncPeriod: 60 * time.Second}, logf.Log)
-		applications = NewApplications(kubeClient, helmClient, charts, clusterCache, fakeClock, &runtimeConfig, gitOpsEngine, logf.Log)
+		applications = NewApplications(kubeClient, helmClient, charts, clusterCache, fakeClock, &runtimeConfig, peers.NewFakeReachability(), gitOpsEngine, logf.Log)
 
 		result, err := applications.Cleanup(context.Background(), application)
 		Expect(err).NotTo(HaveOccurred())
@@ -235,6 +236,51 @@ var _ = Describe("Applications", func() {
 		Expect(versions.ToSlice()).To(BeEmpty())
 	})
 
+	It("should prune resources of previous versions", func() {
+		pod200 := makePod("test-pod1", "2.0.0")
+		pod201 := makePod("test-pod2", "2.0.1")
+
+		clusterCache, clusterCacheClient := fixture.NewTestClusterCacheWithOptions(updateFuncs, &pod200, &pod201)
+		if err := clusterCache.EnsureSynced(); err != nil {
+			Fail("Failed to sync cluster cache: " + err.Error())
+		}
+
+		kubeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithStatusSubresource(&v1.AnyApplication{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Delete: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					gvk := obj.GetObjectKind().GroupVersionKind()
+					resourcePlural, _ := meta.UnsafeGuessKindToResource(gvk)
+					err := clusterCacheClient.Tracker().Delete(
+						gvk.GroupVersion().WithResource(resourcePlural.Resource),
+						obj.GetNamespace(),
+						obj.GetName(),
+					)
+					return err
+				},
+			}).
+			WithObjects(application, &pod200, &pod201).
+			Build()
+
+		charts = NewCharts(context.Background(), helmClient, &ChartsOptions{SyncPeriod: 60 * time.Second}, logf.Log)
+		applications = NewApplications(kubeClient, helmClient, charts, clusterCache, fakeClock, &runtimeConfig, peers.NewFakeReachability(), gitOpsEngine, logf.Log)
+
+		version201, _ := types.NewSpecificVersion("2.0.1")
+		result, err := applications.PruneVersions(context.Background(), application, version201)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Total).To(Equal(1))
+		Expect(result.Deleted).To(Equal(1))
+		Expect(result.ApplicationResourcesPresent).To(BeFalse())
+
+		if err := clusterCache.EnsureSynced(); err != nil {
+			Fail("Failed to sync cluster cache: " + err.Error())
+		}
+
+		versions, _ := applications.GetAllPresentVersions(application)
+		Expect(versions.ToSlice()).To(HaveLen(1))
+	})
+
 	It("should determine target version for the application if version is not set for zone", func() {
 		targetVersion, _ := applications.DetermineTargetVersion(application)
 		Expect(targetVersion.ToString()).To(Equal("2.0.1"))
@@ -264,14 +310,14 @@ var _ = Describe("Applications", func() {
 
 	It("should return aggregated status for application when application is missing", func() {
 		version, _ := types.NewSpecificVersion("2.0.1")
-		status := applications.GetAggregatedStatusVersion(application, version)
+		status := applications.GetAggregatedStatusVersion(context.Background(), application, version)
 
 		Expect(status.HealthStatus.Status).To(Equal(health.HealthStatusMissing))
 		Expect(status.HealthStatus.Message).To(Equal(". "))
 	})
 
 	It("should load application from cluster cache", func() {
-		globalApplication, _ := applications.LoadApplication(application)
+		globalApplication, _ := applications.LoadApplication(context.Background(), application)
 
 		Expect(globalApplication.IsDeployed()).To(BeFalse())
 		Expect(globalApplication.IsPresent()).To(BeFalse())
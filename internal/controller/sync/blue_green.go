@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/cache"
+	"github.com/cockroachdb/errors"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/samber/lo"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/controller/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var versionSuffixReplacer = strings.NewReplacer(".", "-", "+", "-", "_", "-")
+
+// releaseName suffixes the release name with the version for blue/green upgrades,
+// so that the resources of the versions do not collide
+func releaseName(application *v1.AnyApplication, version *types.SpecificVersion) string {
+	if !application.Spec.UpgradeStrategy.IsBlueGreen() || version == nil {
+		return application.Name
+	}
+	return application.Name + "-" + strings.ToLower(versionSuffixReplacer.Replace(version.ToString()))
+}
+
+// isManagedVersionFunc limits the resources managed by a blue/green sync to the synced version,
+// so that the sync does not prune the versions it runs next to
+func (m *applications) isManagedVersionFunc(app *cachedApp) IsManagedResourceFunc {
+	isManaged := m.isManagedFunc(app.instance.InstanceId)
+	version := app.chartKey.Version.ToString()
+	return func(r *cache.Resource) bool {
+		return isManaged(r) && r.Resource.GetLabels()[LABEL_CHART_VERSION] == version
+	}
+}
+
+// targetResources returns the resources of the version. The shared Services of a blue/green
+// upgrade are left out while they still serve another version, they are switched explicitly.
+func (m *applications) targetResources(app *cachedApp) []*unstructured.Unstructured {
+	if !app.application.Spec.UpgradeStrategy.IsBlueGreen() {
+		return app.renderedChart.Resources
+	}
+	sharedServices := mapset.NewSet(app.application.Spec.UpgradeStrategy.GetBlueGreen().Services...)
+	version := app.chartKey.Version.ToString()
+	servingOtherVersion := mapset.NewSet[string]()
+	for _, res := range m.findAvailableApplicationResources(app.application) {
+		if isService(res) && sharedServices.Contains(res.GetName()) && res.GetLabels()[LABEL_CHART_VERSION] != version {
+			servingOtherVersion.Add(res.GetName())
+		}
+	}
+	return lo.Filter(app.renderedChart.Resources, func(res *unstructured.Unstructured, _ int) bool {
+		return !isService(res) || !servingOtherVersion.Contains(res.GetName())
+	})
+}
+
+// SwitchServices points the shared Services of a blue/green upgrade to the release of the version
+// and hands them over to the version. It returns the number of switched Services.
+func (m *applications) SwitchServices(
+	ctx context.Context,
+	application *v1.AnyApplication,
+	version *types.SpecificVersion,
+) (int, error) {
+	blueGreen := application.Spec.UpgradeStrategy.GetBlueGreen()
+	release := releaseName(application, version)
+
+	switched := 0
+	for _, name := range blueGreen.Services {
+		service := &corev1.Service{}
+		if err := m.kubeClient.Get(ctx, client.ObjectKey{Namespace: application.Namespace, Name: name}, service); err != nil {
+			return switched, errors.Wrapf(err, "Failed to get service %s", name)
+		}
+		if service.Spec.Selector[blueGreen.SelectorLabel] == release && service.Labels[LABEL_CHART_VERSION] == version.ToString() {
+			continue
+		}
+		if service.Spec.Selector == nil {
+			service.Spec.Selector = make(map[string]string)
+		}
+		service.Spec.Selector[blueGreen.SelectorLabel] = release
+		if service.Labels == nil {
+			service.Labels = make(map[string]string)
+		}
+		service.Labels[LABEL_CHART_VERSION] = version.ToString()
+		if err := m.kubeClient.Update(ctx, service); err != nil {
+			return switched, errors.Wrapf(err, "Failed to switch service %s", name)
+		}
+		switched++
+		m.log.Info("Switched service to new version", "service", name, "release", release)
+	}
+	return switched, nil
+}
+
+func isService(res *unstructured.Unstructured) bool {
+	gvk := res.GroupVersionKind()
+	return gvk.Group == "" && gvk.Kind == "Service"
+}
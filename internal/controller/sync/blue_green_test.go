@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+
+	"github.com/argoproj/gitops-engine/pkg/cache"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/controller/fixture"
+	"hiro.io/anyapplication/internal/controller/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Blue/green upgrades", func() {
+	var (
+		application *v1.AnyApplication
+		version100  *types.SpecificVersion
+		version200  *types.SpecificVersion
+	)
+
+	makeService := func(version string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "default",
+				Labels: map[string]string{
+					LABEL_INSTANCE_ID:   "default-test-app",
+					LABEL_CHART_VERSION: version,
+					LABEL_MANAGED_BY:    LABEL_VALUE_MANAGED_BY_DCP,
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{
+					"app.kubernetes.io/name":     "web",
+					"app.kubernetes.io/instance": "test-app-" + version,
+				},
+			},
+		}
+	}
+
+	toUnstructured := func(obj runtime.Object) *unstructured.Unstructured {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		Expect(err).NotTo(HaveOccurred())
+		return &unstructured.Unstructured{Object: content}
+	}
+
+	BeforeEach(func() {
+		application = &v1.AnyApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+			Spec: v1.AnyApplicationSpec{
+				UpgradeStrategy: v1.UpgradeStrategySpec{
+					Type:      v1.UpgradeStrategyBlueGreen,
+					BlueGreen: &v1.BlueGreenSpec{Services: []string{"web"}},
+				},
+			},
+		}
+		version100, _ = types.NewSpecificVersion("1.0.0")
+		version200, _ = types.NewSpecificVersion("2.0.0-rc.1")
+	})
+
+	It("should suffix the release name with the version", func() {
+		Expect(releaseName(application, version200)).To(Equal("test-app-2-0-0-rc-1"))
+
+		application.Spec.UpgradeStrategy.Type = v1.UpgradeStrategyInPlace
+		Expect(releaseName(application, version200)).To(Equal("test-app"))
+	})
+
+	It("should leave out shared services serving another version", func() {
+		updateFuncs := []cache.UpdateSettingsFunc{
+			cache.SetPopulateResourceInfoHandler(func(un *unstructured.Unstructured, _ bool) (info any, cacheManifest bool) {
+				return &types.ResourceInfo{ManagedByMark: un.GetLabels()[LABEL_MANAGED_BY]}, true
+			}),
+		}
+		clusterCache, _ := fixture.NewTestClusterCacheWithOptions(updateFuncs, makeService("1.0.0"))
+		Expect(clusterCache.EnsureSynced()).To(Succeed())
+		apps := &applications{clusterCache: clusterCache, log: logf.Log}
+
+		deployment := &unstructured.Unstructured{}
+		deployment.SetAPIVersion("apps/v1")
+		deployment.SetKind("Deployment")
+		deployment.SetName("test-app-2-0-0-rc-1")
+		app := &cachedApp{
+			application: application,
+			chartKey:    &types.ChartKey{Version: *version200},
+			renderedChart: &types.RenderedChart{
+				Resources: []*unstructured.Unstructured{toUnstructured(makeService("2.0.0-rc.1")), deployment},
+			},
+		}
+		Expect(apps.targetResources(app)).To(Equal([]*unstructured.Unstructured{deployment}))
+
+		app.chartKey = &types.ChartKey{Version: *version100}
+		Expect(apps.targetResources(app)).To(HaveLen(2))
+	})
+
+	It("should switch shared services to the new version", func() {
+		kubeClient := fake.NewClientBuilder().WithObjects(makeService("1.0.0")).Build()
+		apps := &applications{kubeClient: kubeClient, log: logf.Log}
+
+		switched, err := apps.SwitchServices(context.Background(), application, version200)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(switched).To(Equal(1))
+
+		service := &corev1.Service{}
+		Expect(kubeClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "web"}, service)).To(Succeed())
+		Expect(service.Spec.Selector).To(Equal(map[string]string{
+			"app.kubernetes.io/name":     "web",
+			"app.kubernetes.io/instance": "test-app-2-0-0-rc-1",
+		}))
+		Expect(service.Labels[LABEL_CHART_VERSION]).To(Equal("2.0.0-rc.1"))
+
+		switched, err = apps.SwitchServices(context.Background(), application, version200)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(switched).To(Equal(0))
+	})
+
+	It("should fail to switch a missing service", func() {
+		kubeClient := fake.NewClientBuilder().Build()
+		apps := &applications{kubeClient: kubeClient, log: logf.Log}
+
+		_, err := apps.SwitchServices(context.Background(), application, version200)
+		Expect(err).To(MatchError(ContainSubstring("Failed to get service web")))
+	})
+})
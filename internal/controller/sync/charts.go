@@ -15,17 +15,20 @@ import (
 	"github.com/go-logr/logr"
 	"hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/helm"
+	"hiro.io/anyapplication/internal/podtemplate"
 )
 
 const (
 	LABEL_MANAGED_BY           = "dcp.hiro.io/managed-by"
 	LABEL_CHART_VERSION        = "dcp.hiro.io/chart-version"
-	LABEL_INSTANCE_ID          = "dcp.hiro.io/instance-id"
+	LABEL_INSTANCE_ID          = types.InstanceIdLabel
 	LABEL_VALUE_MANAGED_BY_DCP = "dcp"
 )
 
 type ChartsOptions struct {
 	SyncPeriod time.Duration
+	// PodTemplates locates pod templates of workloads, built-in kinds are used when not set
+	PodTemplates *podtemplate.Registry
 }
 
 type charts struct {
@@ -159,13 +162,15 @@ func (c *charts) Render(chartKey *types.ChartKey, instance *types.ApplicationIns
 	}
 
 	template, err := c.helmClient.Template(&helm.TemplateArgs{
-		ReleaseName: instance.ReleaseName,
-		RepoUrl:     chartKey.ChartId.RepoUrl,
-		ChartName:   chartKey.ChartId.ChartName,
-		Namespace:   instance.Namespace,
-		Version:     chartKey.Version.ToString(),
-		ValuesYaml:  instance.ValuesYaml,
-		Labels:      labels,
+		ReleaseName:    instance.ReleaseName,
+		RepoUrl:        chartKey.ChartId.RepoUrl,
+		ChartName:      chartKey.ChartId.ChartName,
+		Namespace:      instance.Namespace,
+		Version:        chartKey.Version.ToString(),
+		ValuesYaml:     instance.ValuesYaml,
+		Labels:         labels,
+		Keyring:        instance.Keyring,
+		PostProcessors: c.postProcessors(instance),
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "Helm template failure")
@@ -181,6 +186,15 @@ func (c *charts) Render(chartKey *types.ChartKey, instance *types.ApplicationIns
 	}, nil
 }
 
+// postProcessors returns the post processors specific to the instance
+func (c *charts) postProcessors(instance *types.ApplicationInstance) []helm.PostProcessor {
+	postProcessors := make([]helm.PostProcessor, 0)
+	if len(instance.NodeAffinity) > 0 {
+		postProcessors = append(postProcessors, helm.AddNodeAffinity(instance.NodeAffinity, c.options.PodTemplates, c.logger))
+	}
+	return postProcessors
+}
+
 type ChartVersions struct {
 	charts   sync.Map
 	repoName string
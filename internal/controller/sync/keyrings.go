@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/config"
+	"hiro.io/anyapplication/internal/helm"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const DefaultKeyringSecretKey = "keyring.gpg"
+
+// keyrings resolves the keyring required to verify the chart of an application.
+// The application's own verification settings take precedence over the repository settings.
+type keyrings struct {
+	kubeClient   client.Client
+	repositories []config.RepositoryVerificationConfig
+}
+
+func newKeyrings(kubeClient client.Client, repositories []config.RepositoryVerificationConfig) *keyrings {
+	return &keyrings{
+		kubeClient:   kubeClient,
+		repositories: repositories,
+	}
+}
+
+// Resolve returns nil when the chart of the application does not require verification.
+func (k *keyrings) Resolve(ctx context.Context, application *v1.AnyApplication) (*helm.Keyring, error) {
+	helmSource := application.Spec.Source.HelmSelector
+
+	if helmSource.Verify != nil {
+		secretName := k8stypes.NamespacedName{Namespace: application.Namespace, Name: helmSource.Verify.KeyringSecret}
+		return k.load(ctx, secretName, helmSource.Verify.KeyringKey)
+	}
+	for _, repository := range k.repositories {
+		if sameRepository(repository.Repository, helmSource.Repository) {
+			secretName := k8stypes.NamespacedName{Namespace: repository.KeyringSecretNamespace, Name: repository.KeyringSecretName}
+			return k.load(ctx, secretName, repository.KeyringSecretKey)
+		}
+	}
+	return nil, nil
+}
+
+// keyringResolver returns the keyring of an application resolved at most once
+type keyringResolver func() (*helm.Keyring, error)
+
+// resolveOnce defers resolving the keyring until it is first needed and reuses the result,
+// so that loading an application reads the keyring secret once at most.
+func (k *keyrings) resolveOnce(ctx context.Context, application *v1.AnyApplication) keyringResolver {
+	var (
+		resolved bool
+		keyring  *helm.Keyring
+		err      error
+	)
+	return func() (*helm.Keyring, error) {
+		if !resolved {
+			keyring, err = k.Resolve(ctx, application)
+			resolved = true
+		}
+		return keyring, err
+	}
+}
+
+func (k *keyrings) load(ctx context.Context, secretName k8stypes.NamespacedName, key string) (*helm.Keyring, error) {
+	if key == "" {
+		key = DefaultKeyringSecretKey
+	}
+	secret := &corev1.Secret{}
+	if err := k.kubeClient.Get(ctx, secretName, secret); err != nil {
+		return nil, errors.Mark(
+			errors.Wrapf(err, "Failed to load keyring secret %s", secretName.String()),
+			helm.ErrChartNotVerified,
+		)
+	}
+	data, found := secret.Data[key]
+	if !found || len(data) == 0 {
+		return nil, errors.Mark(
+			errors.Errorf("Keyring secret %s has no key %s", secretName.String(), key),
+			helm.ErrChartNotVerified,
+		)
+	}
+	return &helm.Keyring{Source: secretName.String(), Data: data}, nil
+}
+
+func sameRepository(left string, right string) bool {
+	return strings.TrimSuffix(left, "/") == strings.TrimSuffix(right, "/")
+}
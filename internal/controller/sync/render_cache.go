@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const DefaultRenderCacheSize = 256
+
+const (
+	evictionReasonCapacity = "capacity"
+	evictionReasonStale    = "stale"
+	evictionReasonDeleted  = "deleted"
+)
+
+var (
+	renderCacheEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "anyapplication_render_cache_entries",
+		Help: "Number of rendered chart instances held in memory",
+	})
+	renderCacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "anyapplication_render_cache_hits_total",
+		Help: "Number of rendered chart lookups served from memory",
+	})
+	renderCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "anyapplication_render_cache_misses_total",
+		Help: "Number of rendered chart lookups that required rendering",
+	})
+	renderCacheEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "anyapplication_render_cache_evictions_total",
+		Help: "Number of rendered chart instances evicted from memory",
+	}, []string{"reason"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(renderCacheEntries, renderCacheHits, renderCacheMisses, renderCacheEvictions)
+}
+
+type renderCacheKey struct {
+	appKey   string
+	instance string
+}
+
+type renderCacheEntry struct {
+	key renderCacheKey
+	app *cachedApp
+}
+
+// renderCache is a size bounded LRU of rendered application instances.
+// Entries are grouped by application so that all instances of an application
+// can be dropped at once.
+type renderCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    *list.List
+	index      map[renderCacheKey]*list.Element
+	byApp      map[string]map[renderCacheKey]struct{}
+}
+
+func newRenderCache(maxEntries int) *renderCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultRenderCacheSize
+	}
+	return &renderCache{
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		index:      make(map[renderCacheKey]*list.Element),
+		byApp:      make(map[string]map[renderCacheKey]struct{}),
+	}
+}
+
+func (c *renderCache) Get(appKey string, key *instanceKey) (*cachedApp, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.index[renderCacheKey{appKey: appKey, instance: key.ToString()}]
+	if !ok {
+		renderCacheMisses.Inc()
+		return nil, false
+	}
+	renderCacheHits.Inc()
+	c.entries.MoveToFront(element)
+	return element.Value.(*renderCacheEntry).app, true
+}
+
+func (c *renderCache) Put(appKey string, key *instanceKey, app *cachedApp) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cacheKey := renderCacheKey{appKey: appKey, instance: key.ToString()}
+	if element, ok := c.index[cacheKey]; ok {
+		element.Value.(*renderCacheEntry).app = app
+		c.entries.MoveToFront(element)
+		return
+	}
+
+	c.index[cacheKey] = c.entries.PushFront(&renderCacheEntry{key: cacheKey, app: app})
+	if _, ok := c.byApp[appKey]; !ok {
+		c.byApp[appKey] = make(map[renderCacheKey]struct{})
+	}
+	c.byApp[appKey][cacheKey] = struct{}{}
+
+	for c.entries.Len() > c.maxEntries {
+		c.removeElement(c.entries.Back(), evictionReasonCapacity)
+	}
+	renderCacheEntries.Set(float64(c.entries.Len()))
+}
+
+// RemoveApp drops all instances of the application and returns the number of evicted entries.
+func (c *renderCache) RemoveApp(appKey string) int {
+	return c.removeMatching(appKey, evictionReasonDeleted, func(*cachedApp) bool { return true })
+}
+
+// Retain drops all instances of the application that are not accepted by keep
+// and returns the number of evicted entries.
+func (c *renderCache) Retain(appKey string, keep func(app *cachedApp) bool) int {
+	return c.removeMatching(appKey, evictionReasonStale, func(app *cachedApp) bool { return !keep(app) })
+}
+
+func (c *renderCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+func (c *renderCache) removeMatching(appKey string, reason string, remove func(app *cachedApp) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for cacheKey := range c.byApp[appKey] {
+		element := c.index[cacheKey]
+		if remove(element.Value.(*renderCacheEntry).app) {
+			c.removeElement(element, reason)
+			removed++
+		}
+	}
+	renderCacheEntries.Set(float64(c.entries.Len()))
+	return removed
+}
+
+func (c *renderCache) removeElement(element *list.Element, reason string) {
+	entry := element.Value.(*renderCacheEntry)
+	c.entries.Remove(element)
+	delete(c.index, entry.key)
+	if keys, ok := c.byApp[entry.key.appKey]; ok {
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(c.byApp, entry.key.appKey)
+		}
+	}
+	renderCacheEvictions.WithLabelValues(reason).Inc()
+}
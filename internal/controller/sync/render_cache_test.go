@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"hiro.io/anyapplication/internal/controller/types"
+)
+
+var _ = Describe("RenderCache", func() {
+	var (
+		cache *renderCache
+	)
+
+	makeInstance := func(version string, values string) (*instanceKey, *cachedApp) {
+		specificVersion, err := types.NewSpecificVersion(version)
+		Expect(err).NotTo(HaveOccurred())
+		chartKey := &types.ChartKey{
+			ChartId: types.ChartId{RepoUrl: "https://repo", ChartName: "chart"},
+			Version: *specificVersion,
+		}
+		instance := &types.ApplicationInstance{
+			InstanceId:  "default-app",
+			Name:        "app",
+			Namespace:   "default",
+			ReleaseName: "app",
+			ValuesYaml:  values,
+		}
+		key := &instanceKey{ChartKey: chartKey, Instance: instance}
+		return key, &cachedApp{chartKey: chartKey, instance: instance}
+	}
+
+	BeforeEach(func() {
+		cache = newRenderCache(2)
+	})
+
+	It("should return cached instances", func() {
+		key, app := makeInstance("1.0.0", "")
+		cache.Put("app-default", key, app)
+
+		cached, found := cache.Get("app-default", key)
+		Expect(found).To(BeTrue())
+		Expect(cached).To(BeIdenticalTo(app))
+
+		_, found = cache.Get("other-default", key)
+		Expect(found).To(BeFalse())
+	})
+
+	It("should evict least recently used instances when full", func() {
+		key100, app100 := makeInstance("1.0.0", "")
+		key110, app110 := makeInstance("1.1.0", "")
+		key120, app120 := makeInstance("1.2.0", "")
+
+		cache.Put("app-default", key100, app100)
+		cache.Put("app-default", key110, app110)
+		_, found := cache.Get("app-default", key100)
+		Expect(found).To(BeTrue())
+
+		cache.Put("app-default", key120, app120)
+
+		Expect(cache.Len()).To(Equal(2))
+		_, found = cache.Get("app-default", key110)
+		Expect(found).To(BeFalse())
+		_, found = cache.Get("app-default", key100)
+		Expect(found).To(BeTrue())
+		_, found = cache.Get("app-default", key120)
+		Expect(found).To(BeTrue())
+	})
+
+	It("should retain only accepted instances of application", func() {
+		key100, app100 := makeInstance("1.0.0", "")
+		key100Values, app100Values := makeInstance("1.0.0", "replicas: 2")
+
+		cache.Put("app-default", key100, app100)
+		cache.Put("app-default", key100Values, app100Values)
+
+		evicted := cache.Retain("app-default", func(app *cachedApp) bool {
+			return app.instance.ValuesYaml == "replicas: 2"
+		})
+		Expect(evicted).To(Equal(1))
+
+		_, found := cache.Get("app-default", key100)
+		Expect(found).To(BeFalse())
+		_, found = cache.Get("app-default", key100Values)
+		Expect(found).To(BeTrue())
+	})
+
+	It("should drop all instances of removed application", func() {
+		key100, app100 := makeInstance("1.0.0", "")
+		key110, app110 := makeInstance("1.1.0", "")
+
+		cache.Put("app-default", key100, app100)
+		cache.Put("other-default", key110, app110)
+
+		Expect(cache.RemoveApp("app-default")).To(Equal(1))
+		Expect(cache.Len()).To(Equal(1))
+
+		_, found := cache.Get("other-default", key110)
+		Expect(found).To(BeTrue())
+	})
+})
@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const renderStoreFileSuffix = ".json"
+
+var (
+	renderStoreHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "anyapplication_render_store_hits_total",
+		Help: "Number of rendered chart lookups served from the on-disk cache",
+	})
+	renderStoreCorrupted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "anyapplication_render_store_corrupted_total",
+		Help: "Number of on-disk rendered chart entries discarded by integrity checks",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(renderStoreHits, renderStoreCorrupted)
+}
+
+type renderStoreEntry struct {
+	Revision  string            `json:"revision"`
+	Checksum  string            `json:"checksum"`
+	Resources []json.RawMessage `json:"resources"`
+}
+
+// renderStore persists rendered chart resources on disk so that present versions
+// can be assessed after a restart without access to the chart repository.
+// Entries are stored per application and keyed by the instance revision.
+type renderStore struct {
+	dir string
+	log logr.Logger
+}
+
+// newRenderStore returns nil when no directory is configured, which disables persistence.
+func newRenderStore(dir string, log logr.Logger) *renderStore {
+	if dir == "" {
+		return nil
+	}
+	return &renderStore{
+		dir: dir,
+		log: log.WithName("RenderStore"),
+	}
+}
+
+func (s *renderStore) Load(appKey string, revision string) ([]*unstructured.Unstructured, bool) {
+	if s == nil {
+		return nil, false
+	}
+	path := s.entryPath(appKey, revision)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.log.Error(err, "Failed to read rendered chart", "path", path)
+		}
+		return nil, false
+	}
+
+	resources, err := decodeRenderStoreEntry(data, revision)
+	if err != nil {
+		renderStoreCorrupted.Inc()
+		s.log.Error(err, "Discarding corrupted rendered chart", "path", path)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			s.log.Error(err, "Failed to remove corrupted rendered chart", "path", path)
+		}
+		return nil, false
+	}
+	renderStoreHits.Inc()
+	return resources, true
+}
+
+func (s *renderStore) Store(appKey string, revision string, resources []*unstructured.Unstructured) error {
+	if s == nil {
+		return nil
+	}
+	data, err := encodeRenderStoreEntry(revision, resources)
+	if err != nil {
+		return err
+	}
+
+	appDir := filepath.Join(s.dir, appKey)
+	if err := os.MkdirAll(appDir, 0o750); err != nil {
+		return errors.Wrap(err, "Failed to create render cache directory")
+	}
+	tmp, err := os.CreateTemp(appDir, revision+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "Failed to create temporary render cache file")
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "Failed to write rendered chart")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "Failed to write rendered chart")
+	}
+	if err := os.Rename(tmp.Name(), s.entryPath(appKey, revision)); err != nil {
+		return errors.Wrap(err, "Failed to persist rendered chart")
+	}
+	return nil
+}
+
+// RemoveApp drops all persisted revisions of the application.
+func (s *renderStore) RemoveApp(appKey string) error {
+	if s == nil {
+		return nil
+	}
+	if err := os.RemoveAll(filepath.Join(s.dir, appKey)); err != nil {
+		return errors.Wrap(err, "Failed to remove rendered charts")
+	}
+	return nil
+}
+
+// Retain drops all persisted revisions of the application except the given ones
+// and returns the number of removed entries.
+func (s *renderStore) Retain(appKey string, revisions mapset.Set[string]) (int, error) {
+	if s == nil {
+		return 0, nil
+	}
+	entries, err := os.ReadDir(filepath.Join(s.dir, appKey))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrap(err, "Failed to list rendered charts")
+	}
+	removed := 0
+	for _, entry := range entries {
+		revision, isEntry := strings.CutSuffix(entry.Name(), renderStoreFileSuffix)
+		if !isEntry || revisions.Contains(revision) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, appKey, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return removed, errors.Wrap(err, "Failed to remove rendered chart")
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (s *renderStore) entryPath(appKey string, revision string) string {
+	return filepath.Join(s.dir, appKey, revision+renderStoreFileSuffix)
+}
+
+func encodeRenderStoreEntry(revision string, resources []*unstructured.Unstructured) ([]byte, error) {
+	objects := make([]json.RawMessage, 0, len(resources))
+	for _, resource := range resources {
+		object, err := resource.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to encode resource %s", getFullName(resource))
+		}
+		// Stored compacted, so the checksum matches the bytes read back
+		compacted := bytes.Buffer{}
+		if err := json.Compact(&compacted, object); err != nil {
+			return nil, errors.Wrapf(err, "Failed to encode resource %s", getFullName(resource))
+		}
+		objects = append(objects, compacted.Bytes())
+	}
+	data, err := json.Marshal(&renderStoreEntry{
+		Revision:  revision,
+		Checksum:  renderStoreChecksum(objects),
+		Resources: objects,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode rendered chart")
+	}
+	return data, nil
+}
+
+func decodeRenderStoreEntry(data []byte, revision string) ([]*unstructured.Unstructured, error) {
+	entry := renderStoreEntry{}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode rendered chart")
+	}
+	if entry.Revision != revision {
+		return nil, errors.Errorf("Revision mismatch: expected %s, found %s", revision, entry.Revision)
+	}
+	if checksum := renderStoreChecksum(entry.Resources); checksum != entry.Checksum {
+		return nil, errors.Errorf("Checksum mismatch: expected %s, found %s", entry.Checksum, checksum)
+	}
+	resources := make([]*unstructured.Unstructured, 0, len(entry.Resources))
+	for _, object := range entry.Resources {
+		resource := &unstructured.Unstructured{}
+		if err := resource.UnmarshalJSON(object); err != nil {
+			return nil, errors.Wrap(err, "Failed to decode resource")
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func renderStoreChecksum(objects []json.RawMessage) string {
+	hash := sha256.New()
+	for _, object := range objects {
+		hash.Write(object)
+		hash.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/argoproj/gitops-engine/pkg/cache"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
+	"hiro.io/anyapplication/internal/config"
+	"hiro.io/anyapplication/internal/controller/fixture"
+	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/peers"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("RenderStore", func() {
+	var (
+		store     *renderStore
+		resources []*unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		store = newRenderStore(GinkgoT().TempDir(), logr.Discard())
+		resources = []*unstructured.Unstructured{
+			{Object: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "nginx", "namespace": "default"},
+				"spec":       map[string]any{"replicas": int64(2)},
+			}},
+		}
+	})
+
+	It("should be disabled without directory", func() {
+		disabled := newRenderStore("", logr.Discard())
+		Expect(disabled.Store("app-default", "rev1", resources)).To(Succeed())
+		_, found := disabled.Load("app-default", "rev1")
+		Expect(found).To(BeFalse())
+	})
+
+	It("should load stored resources", func() {
+		Expect(store.Store("app-default", "rev1", resources)).To(Succeed())
+
+		loaded, found := store.Load("app-default", "rev1")
+		Expect(found).To(BeTrue())
+		Expect(loaded).To(Equal(resources))
+
+		_, found = store.Load("app-default", "rev2")
+		Expect(found).To(BeFalse())
+	})
+
+	It("should discard corrupted entries", func() {
+		Expect(store.Store("app-default", "rev1", resources)).To(Succeed())
+		path := store.entryPath("app-default", "rev1")
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		data = []byte(string(data[:len(data)-20]) + `"replicas":3}}]}`)
+		Expect(os.WriteFile(path, data, 0o600)).To(Succeed())
+
+		_, found := store.Load("app-default", "rev1")
+		Expect(found).To(BeFalse())
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+
+	It("should reject entries stored under another revision", func() {
+		Expect(store.Store("app-default", "rev1", resources)).To(Succeed())
+		Expect(os.Rename(store.entryPath("app-default", "rev1"), store.entryPath("app-default", "rev2"))).To(Succeed())
+
+		_, found := store.Load("app-default", "rev2")
+		Expect(found).To(BeFalse())
+	})
+
+	It("should retain only given revisions and remove application", func() {
+		Expect(store.Store("app-default", "rev1", resources)).To(Succeed())
+		Expect(store.Store("app-default", "rev2", resources)).To(Succeed())
+
+		removed, err := store.Retain("app-default", mapset.NewSet("rev2"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(Equal(1))
+		_, found := store.Load("app-default", "rev1")
+		Expect(found).To(BeFalse())
+		_, found = store.Load("app-default", "rev2")
+		Expect(found).To(BeTrue())
+
+		Expect(store.RemoveApp("app-default")).To(Succeed())
+		Expect(filepath.Join(store.dir, "app-default")).NotTo(BeADirectory())
+	})
+})
+
+// renderingCharts renders a single Deployment of the instance
+type renderingCharts struct {
+	indexedCharts
+}
+
+func (c *renderingCharts) Render(chartKey *types.ChartKey, instance *types.ApplicationInstance) (*types.RenderedChart, error) {
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetName("nginx")
+	deployment.SetNamespace(instance.Namespace)
+	deployment.SetLabels(map[string]string{
+		LABEL_INSTANCE_ID:   instance.InstanceId,
+		LABEL_CHART_VERSION: chartKey.Version.ToString(),
+		LABEL_MANAGED_BY:    LABEL_VALUE_MANAGED_BY_DCP,
+	})
+	return &types.RenderedChart{Key: *chartKey, Instance: *instance, Resources: []*unstructured.Unstructured{deployment}}, nil
+}
+
+var _ = Describe("Offline restart", func() {
+	It("should load the deployed version from the render store without the chart repository", func() {
+		application := &v1.AnyApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+			Spec: v1.AnyApplicationSpec{
+				Source: v1.ApplicationSourceSpec{
+					HelmSelector: &v1.ApplicationSourceHelm{Repository: "test-repo", Chart: "test-chart", Version: ">=1.0.0"},
+				},
+			},
+			Status: v1.AnyApplicationStatus{
+				Ownership: v1.OwnershipStatus{
+					Owner:         "zone",
+					State:         v1.OperationalGlobalState,
+					TargetVersion: "2.0.0",
+					Placements:    []v1.Placement{{Zone: "zone"}},
+				},
+				Zones: []v1.ZoneStatus{{ZoneId: "zone", ChartVersion: "2.0.0"}},
+			},
+		}
+		deployed := &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nginx",
+				Namespace: "default",
+				Labels: map[string]string{
+					LABEL_INSTANCE_ID:   "default-test-app",
+					LABEL_CHART_VERSION: "2.0.0",
+					LABEL_MANAGED_BY:    LABEL_VALUE_MANAGED_BY_DCP,
+				},
+			},
+		}
+		updateFuncs := []cache.UpdateSettingsFunc{
+			cache.SetPopulateResourceInfoHandler(func(un *unstructured.Unstructured, _ bool) (info any, cacheManifest bool) {
+				return &types.ResourceInfo{ManagedByMark: un.GetLabels()[LABEL_MANAGED_BY]}, true
+			}),
+		}
+		clusterCache, _ := fixture.NewTestClusterCacheWithOptions(updateFuncs, deployed)
+		Expect(clusterCache.EnsureSynced()).To(Succeed())
+		runtimeConfig := &config.ApplicationRuntimeConfig{ZoneId: "zone", RenderCacheDir: GinkgoT().TempDir()}
+		newApplications := func(charts types.Charts) types.Applications {
+			return NewApplications(fake.NewClientBuilder().Build(), nil, charts, clusterCache, clock.NewFakeClock(),
+				runtimeConfig, peers.NewFakeReachability(), fixture.NewFakeGitopsEngine(), logf.Log)
+		}
+
+		online := &renderingCharts{indexedCharts{versions: []string{"1.0.0", "2.0.0"}}}
+		globalApplication, err := newApplications(online).LoadApplication(context.Background(), application)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(globalApplication.IsPresent()).To(BeTrue())
+
+		// a restarted controller has an empty in-memory render cache and no chart repository
+		offline := newApplications(&unavailableCharts{})
+		globalApplication, err = offline.LoadApplication(context.Background(), application)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(globalApplication.IsPresent()).To(BeTrue())
+		Expect(globalApplication.IsVersionChanged()).To(BeFalse())
+		version, err := offline.DetermineTargetVersion(application)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version.ToString()).To(Equal("2.0.0"))
+	})
+})
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/cockroachdb/errors"
+	"github.com/samber/lo"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/controller/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RestartedAtAnnotation is the pod template annotation kubectl sets to restart a workload
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+var restartableKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+
+// RestartWorkloads restarts the workloads of the version which are not healthy, like crash-looping
+// Deployments, by rolling their pods. It returns the number of restarted workloads.
+func (m *applications) RestartWorkloads(
+	ctx context.Context,
+	application *v1.AnyApplication,
+	version *types.SpecificVersion,
+) (int, error) {
+	restartedAt := m.clock.NowTime().Format(time.RFC3339)
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]string{RestartedAtAnnotation: restartedAt},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to build restart patch")
+	}
+
+	restarted := 0
+	for _, res := range m.findAvailableApplicationResources(application) {
+		if !isRestartable(res) || res.GetLabels()[LABEL_CHART_VERSION] != version.ToString() {
+			continue
+		}
+		resourceHealth, err := health.GetResourceHealth(res, nil)
+		if err != nil {
+			return restarted, errors.Wrapf(err, "Failed to get health of %s %s", res.GetKind(), res.GetName())
+		}
+		if resourceHealth == nil || resourceHealth.Status == health.HealthStatusHealthy {
+			continue
+		}
+		workload := &unstructured.Unstructured{}
+		workload.SetGroupVersionKind(res.GroupVersionKind())
+		workload.SetNamespace(res.GetNamespace())
+		workload.SetName(res.GetName())
+		if err := m.kubeClient.Patch(ctx, workload, client.RawPatch(k8stypes.MergePatchType, patch)); err != nil {
+			return restarted, errors.Wrapf(err, "Failed to restart %s %s", res.GetKind(), res.GetName())
+		}
+		restarted++
+		m.log.Info("Restarted workload", "kind", res.GetKind(), "name", res.GetName(), "health", resourceHealth.Status)
+	}
+	return restarted, nil
+}
+
+func isRestartable(res *unstructured.Unstructured) bool {
+	gvk := res.GroupVersionKind()
+	return gvk.Group == "apps" && lo.Contains(restartableKinds, gvk.Kind)
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/cache"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
+	"hiro.io/anyapplication/internal/controller/fixture"
+	"hiro.io/anyapplication/internal/controller/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Restart of workloads", func() {
+	var (
+		application *v1.AnyApplication
+		version     *types.SpecificVersion
+	)
+
+	makeDeployment := func(name string, chartVersion string, progressing corev1.ConditionStatus) *appsv1.Deployment {
+		reason := "NewReplicaSetAvailable"
+		if progressing == corev1.ConditionFalse {
+			reason = "ProgressDeadlineExceeded"
+		}
+		return &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					LABEL_INSTANCE_ID:   "default-test-app",
+					LABEL_CHART_VERSION: chartVersion,
+					LABEL_MANAGED_BY:    LABEL_VALUE_MANAGED_BY_DCP,
+				},
+			},
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: progressing, Reason: reason},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		application = &v1.AnyApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+		}
+		version, _ = types.NewSpecificVersion("1.0.0")
+	})
+
+	It("should restart the unhealthy workloads of the version", func() {
+		crashing := makeDeployment("crashing", "1.0.0", corev1.ConditionFalse)
+		healthy := makeDeployment("healthy", "1.0.0", corev1.ConditionTrue)
+		otherVersion := makeDeployment("other-version", "0.9.0", corev1.ConditionFalse)
+
+		updateFuncs := []cache.UpdateSettingsFunc{
+			cache.SetPopulateResourceInfoHandler(func(un *unstructured.Unstructured, _ bool) (info any, cacheManifest bool) {
+				return &types.ResourceInfo{ManagedByMark: un.GetLabels()[LABEL_MANAGED_BY]}, true
+			}),
+		}
+		clusterCache, _ := fixture.NewTestClusterCacheWithOptions(updateFuncs, crashing, healthy, otherVersion)
+		Expect(clusterCache.EnsureSynced()).To(Succeed())
+		kubeClient := fake.NewClientBuilder().WithObjects(crashing.DeepCopy(), healthy.DeepCopy(), otherVersion.DeepCopy()).Build()
+		fakeClock := clock.NewFakeClock()
+		apps := &applications{clusterCache: clusterCache, kubeClient: kubeClient, clock: fakeClock, log: logf.Log}
+
+		restarted, err := apps.RestartWorkloads(context.Background(), application, version)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted).To(Equal(1))
+
+		restartedAt := func(name string) string {
+			deployment := &appsv1.Deployment{}
+			Expect(kubeClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, deployment)).To(Succeed())
+			return deployment.Spec.Template.Annotations[RestartedAtAnnotation]
+		}
+		Expect(restartedAt("crashing")).To(Equal(fakeClock.NowTime().Format(time.RFC3339)))
+		Expect(restartedAt("healthy")).To(BeEmpty())
+		Expect(restartedAt("other-version")).To(BeEmpty())
+	})
+})
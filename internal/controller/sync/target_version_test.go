@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package sync
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
+	"hiro.io/anyapplication/internal/config"
+	"hiro.io/anyapplication/internal/controller/fixture"
+	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/peers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// indexedCharts knows a fixed set of chart versions, the latest one matches any range
+type indexedCharts struct {
+	FakeCharts
+	versions []string
+}
+
+func (c *indexedCharts) AddAndGetLatest(chartName string, repoUrl string, chartVersion types.ChartVersion) (*types.ChartKey, error) {
+	version := c.versions[len(c.versions)-1]
+	if _, isSpecific := chartVersion.(*types.SpecificVersion); isSpecific {
+		if !lo.Contains(c.versions, chartVersion.ToString()) {
+			return nil, errors.Errorf("Specific version %s not found for chart %s", chartVersion.ToString(), chartName)
+		}
+		version = chartVersion.ToString()
+	}
+	return c.FakeCharts.AddAndGetLatest(chartName, repoUrl, lo.Must(types.NewSpecificVersion(version)))
+}
+
+// unavailableCharts cannot reach the chart repository
+type unavailableCharts struct {
+	FakeCharts
+}
+
+func (c *unavailableCharts) AddAndGetLatest(chartName string, repoUrl string, chartVersion types.ChartVersion) (*types.ChartKey, error) {
+	return nil, errors.Errorf("Failed to fetch the index of repository %s", repoUrl)
+}
+
+func (c *unavailableCharts) Render(chartKey *types.ChartKey, instance *types.ApplicationInstance) (*types.RenderedChart, error) {
+	return nil, errors.Errorf("Failed to pull chart %s", chartKey.ToString())
+}
+
+var _ = Describe("Target version", func() {
+	var (
+		application *v1.AnyApplication
+		apps        *applications
+	)
+
+	BeforeEach(func() {
+		application = &v1.AnyApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-app", Namespace: "default"},
+			Spec: v1.AnyApplicationSpec{
+				Source: v1.ApplicationSourceSpec{
+					HelmSelector: &v1.ApplicationSourceHelm{
+						Repository: "test-repo",
+						Chart:      "test-chart",
+						Version:    ">=1.0.0",
+					},
+				},
+			},
+			Status: v1.AnyApplicationStatus{
+				Ownership: v1.OwnershipStatus{Owner: "zone-a"},
+			},
+		}
+		apps = &applications{
+			charts: &indexedCharts{versions: []string{"1.0.0", "2.0.0", "2.1.0"}},
+			config: &config.ApplicationRuntimeConfig{ZoneId: "zone-b"},
+			log:    logf.Log,
+		}
+	})
+
+	It("should resolve the version range until the owner publishes a version", func() {
+		version, err := apps.DetermineTargetVersion(application)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version.ToString()).To(Equal("2.1.0"))
+	})
+
+	It("should deploy the version published by the owner", func() {
+		application.Status.Ownership.TargetVersion = "2.0.0"
+
+		version, err := apps.DetermineTargetVersion(application)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version.ToString()).To(Equal("2.0.0"))
+	})
+
+	It("should keep the previous version until the zone is admitted to the rollout", func() {
+		application.Status.Ownership.TargetVersion = "2.1.0"
+		application.Status.Ownership.Rollout = &v1.RolloutStatus{
+			Version:         "2.1.0",
+			PreviousVersion: "2.0.0",
+			Phase:           v1.RolloutPhaseProgressing,
+			Zones:           []string{"zone-a"},
+		}
+
+		version, err := apps.DetermineTargetVersion(application)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version.ToString()).To(Equal("2.0.0"))
+	})
+
+	It("should fail to load the application when the owner cannot resolve the version range", func() {
+		application.Status.Ownership.Owner = "zone-b"
+		clusterCache, _ := fixture.NewTestClusterCacheWithOptions(nil)
+		Expect(clusterCache.EnsureSynced()).To(Succeed())
+		apps := NewApplications(fake.NewClientBuilder().Build(), nil, &unavailableCharts{}, clusterCache, clock.NewFakeClock(),
+			&config.ApplicationRuntimeConfig{ZoneId: "zone-b"}, peers.NewFakeReachability(), fixture.NewFakeGitopsEngine(), logf.Log)
+
+		_, err := apps.LoadApplication(context.Background(), application)
+		Expect(err).To(MatchError(ContainSubstring("Failed to resolve target version: Failed to fetch the index of repository test-repo")))
+	})
+
+	It("should fail when the published version cannot be fetched", func() {
+		application.Status.Ownership.TargetVersion = "3.0.0"
+
+		_, err := apps.DetermineTargetVersion(application)
+		var unavailableErr *types.VersionUnavailableError
+		Expect(errors.As(err, &unavailableErr)).To(BeTrue())
+		Expect(unavailableErr.Version).To(Equal("3.0.0"))
+		Expect(err.Error()).To(Equal("Chart version 3.0.0 is not available: Specific version 3.0.0 not found for chart test-chart"))
+	})
+})
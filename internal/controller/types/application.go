@@ -4,6 +4,8 @@
 package types
 
 import (
+	"time"
+
 	"github.com/samber/mo"
 	v1 "hiro.io/anyapplication/api/v1"
 )
@@ -32,7 +34,11 @@ type NextStateResult struct {
 	ConditionsToAdd    mo.Option[*v1.ConditionStatus]
 	ConditionsToRemove []*v1.ConditionStatus
 	NewVersion         mo.Option[*SpecificVersion]
-	Jobs               NextJobs
+	Placements         mo.Option[[]v1.Placement]
+	Rollout            mo.Option[*v1.RolloutStatus]
+	// ResetAcknowledged is the reset of failures applied by the zone
+	ResetAcknowledged mo.Option[time.Time]
+	Jobs              NextJobs
 }
 
 type StatusResult struct {
@@ -50,3 +56,18 @@ type GlobalApplication interface {
 	HasZoneStatus() bool
 	DeriveNewStatus(jobConditions JobApplicationCondition, jobFactory AsyncJobFactory) StatusResult
 }
+
+// ZoneReachability tells zones which do not respond apart from zones reporting their status
+type ZoneReachability interface {
+	IsUnreachable(zoneId string) bool
+	// Zones describes the own zone and the reachable peer zones
+	Zones() []ZoneInfo
+}
+
+// ZoneInfo describes a zone for placement decisions
+type ZoneInfo struct {
+	ZoneId string
+	Labels map[string]string
+	// Applications is the number of applications placed in the zone
+	Applications int
+}
@@ -69,14 +69,18 @@ type Applications interface {
 	DetermineTargetVersion(application *v1.AnyApplication) (*SpecificVersion, error)
 
 	GetInstanceId(application *v1.AnyApplication) string
-	LoadApplication(application *v1.AnyApplication) (GlobalApplication, error)
+	LoadApplication(ctx context.Context, application *v1.AnyApplication) (GlobalApplication, error)
 
-	GetRenderedChart(application *v1.AnyApplication) (*RenderedChart, error)
+	GetRenderedChart(ctx context.Context, application *v1.AnyApplication, version *SpecificVersion) (*RenderedChart, error)
 
-	GetAggregatedStatusVersion(application *v1.AnyApplication, version *SpecificVersion) *AggregatedStatus
+	GetAggregatedStatusVersion(ctx context.Context, application *v1.AnyApplication, version *SpecificVersion) *AggregatedStatus
 	SyncVersion(ctx context.Context, application *v1.AnyApplication, version *SpecificVersion) (*SyncResult, error)
 	DeleteVersion(ctx context.Context, application *v1.AnyApplication, version *SpecificVersion) (*DeleteResult, error)
+	PruneVersions(ctx context.Context, application *v1.AnyApplication, version *SpecificVersion) (*DeleteResult, error)
+	SwitchServices(ctx context.Context, application *v1.AnyApplication, version *SpecificVersion) (int, error)
+	RestartWorkloads(ctx context.Context, application *v1.AnyApplication, version *SpecificVersion) (int, error)
 	Cleanup(ctx context.Context, application *v1.AnyApplication) ([]*DeleteResult, error)
+	Forget(application *v1.AnyApplication)
 }
 
 type ResourceInfo struct {
@@ -5,13 +5,29 @@ package types
 
 import (
 	"encoding/json"
+	"strings"
 
 	semver "github.com/Masterminds/semver/v3"
 	"github.com/pkg/errors"
 	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/helm"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// VersionUnavailableError tells that the zone cannot fetch the chart version published by the owner
+type VersionUnavailableError struct {
+	Version string
+	Err     error
+}
+
+func (e *VersionUnavailableError) Error() string {
+	return "Chart version " + e.Version + " is not available: " + e.Err.Error()
+}
+
+func (e *VersionUnavailableError) Unwrap() error {
+	return e.Err
+}
+
 type ChartId struct {
 	RepoUrl   string
 	ChartName string
@@ -33,17 +49,30 @@ func (ck *ChartKey) ToString() string {
 	return ck.ChartId.RepoUrl + "/" + ck.ChartId.ChartName + ":" + ck.Version.ToString()
 }
 
+// InstanceIdLabel is set on all resources and pod templates of an application instance
+const InstanceIdLabel = "dcp.hiro.io/instance-id"
+
 type ApplicationInstance struct {
 	InstanceId  string
 	Name        string
 	Namespace   string
 	ReleaseName string
 	ValuesYaml  string
+	Keyring     *helm.Keyring
+	// NodeAffinity of the placement in the local zone
+	NodeAffinity []string
 }
 
 func (ai *ApplicationInstance) ToString() string {
-	return ai.Namespace + "/" + ai.Name + " (" + ai.InstanceId + ") " +
+	str := ai.Namespace + "/" + ai.Name + " (" + ai.InstanceId + ") " +
 		ai.ReleaseName + " values{" + ai.ValuesYaml + "}"
+	if ai.Keyring != nil {
+		str += " keyring{" + ai.Keyring.Source + "@" + ai.Keyring.Fingerprint() + "}"
+	}
+	if len(ai.NodeAffinity) > 0 {
+		str += " nodeAffinity{" + strings.Join(ai.NodeAffinity, ",") + "}"
+	}
+	return str
 }
 
 type RenderedChart struct {
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"time"
+
+	v1 "hiro.io/anyapplication/api/v1"
+)
+
+// RetriesReset tells whether the retries of a failed job start over. They start over once the
+// cool-down passed since the failure, or when a reset of failures was requested after it.
+func RetriesReset(application *v1.AnyApplication, failure *v1.ConditionStatus, defaultCooldown time.Duration, now time.Time) bool {
+	failedAt := failure.LastTransitionTime.Time
+	cooldown := application.Spec.RecoverStrategy.Cooldown.Duration
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+	if cooldown > 0 && !now.Before(failedAt.Add(cooldown)) {
+		return true
+	}
+	resetAt, found := ResetRequestedAt(application)
+	return found && failedAt.Before(resetAt)
+}
+
+// ResetRequestedAt returns when a reset of failures was requested through the annotation
+func ResetRequestedAt(application *v1.AnyApplication) (time.Time, bool) {
+	value, found := application.Annotations[v1.ResetFailuresAnnotation]
+	if !found {
+		return time.Time{}, false
+	}
+	resetAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return resetAt, true
+}
+
+// PendingReset returns the requested reset of failures which the zone has not acknowledged yet
+func PendingReset(application *v1.AnyApplication, zoneId string) (time.Time, bool) {
+	resetAt, found := ResetRequestedAt(application)
+	if !found {
+		return time.Time{}, false
+	}
+	zoneStatus, found := application.Status.GetStatusFor(zoneId)
+	if !found {
+		return time.Time{}, false
+	}
+	if zoneStatus.LastResetTime != nil && !zoneStatus.LastResetTime.Time.Before(resetAt) {
+		return time.Time{}, false
+	}
+	return resetAt, true
+}
+
+// ClearedByReset tells whether a reset of failures at resetAt removes the condition. The reset
+// removes the failures and the retry counters recorded before it.
+func ClearedByReset(condition *v1.ConditionStatus, resetAt time.Time) bool {
+	if !condition.LastTransitionTime.Time.Before(resetAt) {
+		return false
+	}
+	if condition.RetryAttempt > 0 {
+		return true
+	}
+	switch condition.Type {
+	case v1.DeploymentConditionType:
+		return condition.Status == string(v1.DeploymentStatusFailure)
+	case v1.UndeploymentConditionType:
+		return condition.Status == string(v1.UndeploymentStatusFailure)
+	case v1.RemediationConditionType:
+		return condition.Status == string(v1.RemediationStatusFailed) ||
+			condition.Status == string(v1.RemediationStatusExhausted)
+	}
+	return false
+}
+
+// NextRetryAttempt returns the retry attempt of a job which takes over from the job that
+// reported the condition of the zone. Attempts count up as long as the jobs fail.
+func NextRetryAttempt(
+	application *v1.AnyApplication,
+	zoneId string,
+	conditionType v1.ApplicationConditionType,
+	failureStatus string,
+	defaultCooldown time.Duration,
+	now time.Time,
+) int {
+	zoneStatus, found := application.Status.GetStatusFor(zoneId)
+	if !found {
+		return 0
+	}
+	condition, found := zoneStatus.FindCondition(conditionType)
+	if !found || condition.Status != failureStatus || RetriesReset(application, condition, defaultCooldown, now) {
+		return 0
+	}
+	return condition.RetryAttempt + 1
+}
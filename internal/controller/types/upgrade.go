@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"time"
+
+	v1 "hiro.io/anyapplication/api/v1"
+)
+
+// BakeDeadline returns until when the previous versions of a blue/green upgrade are kept.
+// The bake time starts once the zone reports the deployment of the new version done.
+func BakeDeadline(application *v1.AnyApplication, zoneId string) (time.Time, bool) {
+	zoneStatus, found := application.Status.GetStatusFor(zoneId)
+	if !found {
+		return time.Time{}, false
+	}
+	condition, found := zoneStatus.FindCondition(v1.DeploymentConditionType)
+	if !found || condition.Status != string(v1.DeploymentStatusDone) {
+		return time.Time{}, false
+	}
+	bakeTime := application.Spec.UpgradeStrategy.GetBlueGreen().BakeTime.Duration
+	return condition.LastTransitionTime.Add(bakeTime), true
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"hiro.io/anyapplication/internal/config"
+	"hiro.io/anyapplication/internal/podtemplate"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -30,7 +32,7 @@ var _ = Describe("AddLabels", func() {
 			"foo": "bar",
 		}
 
-		result := AddLabels(labels, log)(obj)
+		result := AddLabels(labels, podtemplate.DefaultRegistry(), log)(obj)
 		Expect(result.GetLabels()).To(HaveKeyWithValue("foo", "bar"))
 	})
 
@@ -47,7 +49,7 @@ var _ = Describe("AddLabels", func() {
 			"foo": "bar",
 		}
 
-		result := AddLabels(labels, log)(obj)
+		result := AddLabels(labels, podtemplate.DefaultRegistry(), log)(obj)
 		Expect(result.GetLabels()).To(HaveKeyWithValue("foo", "bar"))
 		Expect(result.GetLabels()).To(HaveKeyWithValue("existing", "label"))
 	})
@@ -71,7 +73,7 @@ var _ = Describe("AddLabels", func() {
 			"foo": "bar",
 		}
 
-		result := AddLabels(newLabels, log)(obj)
+		result := AddLabels(newLabels, podtemplate.DefaultRegistry(), log)(obj)
 		labelsMap, found, err := unstructured.NestedStringMap(result.Object, "spec", "template", "metadata", "labels")
 		Expect(err).NotTo(HaveOccurred())
 		Expect(found).To(BeTrue())
@@ -95,7 +97,7 @@ var _ = Describe("AddLabels", func() {
 			"foo": "bar",
 		}
 
-		result := AddLabels(newLabels, log)(obj)
+		result := AddLabels(newLabels, podtemplate.DefaultRegistry(), log)(obj)
 		labelsMap, found, err := unstructured.NestedStringMap(result.Object, "spec", "template", "metadata", "labels")
 		Expect(err).NotTo(HaveOccurred())
 		Expect(found).To(BeTrue())
@@ -123,7 +125,7 @@ var _ = Describe("AddLabels", func() {
 			"foo": "bar",
 		}
 
-		result := AddLabels(newLabels, log)(obj)
+		result := AddLabels(newLabels, podtemplate.DefaultRegistry(), log)(obj)
 		labelsMap, found, err := unstructured.NestedStringMap(result.Object, "spec", "template", "metadata", "labels")
 		Expect(err).NotTo(HaveOccurred())
 		Expect(found).To(BeTrue())
@@ -131,4 +133,50 @@ var _ = Describe("AddLabels", func() {
 		Expect(labelsMap).To(HaveKeyWithValue("existing", "label"))
 	})
 
+	It("should add labels to the job template of CronJob", func() {
+		obj := unstructured.Unstructured{}
+		obj.Object = map[string]interface{}{
+			"apiVersion": "batch/v1",
+			"kind":       "CronJob",
+			"spec": map[string]interface{}{
+				"jobTemplate": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{},
+					},
+				},
+			},
+		}
+
+		result := AddLabels(map[string]string{"foo": "bar"}, podtemplate.DefaultRegistry(), log)(obj)
+		labelsMap, found, err := unstructured.NestedStringMap(result.Object,
+			"spec", "jobTemplate", "spec", "template", "metadata", "labels")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(labelsMap).To(HaveKeyWithValue("foo", "bar"))
+	})
+
+	It("should add labels to the pod template of configured kinds", func() {
+		podTemplates, err := podtemplate.NewRegistry([]config.PodTemplateConfig{
+			{Group: "example.com", Kind: "Worker", Path: "spec.worker.template"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		obj := unstructured.Unstructured{}
+		obj.Object = map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Worker",
+			"spec": map[string]interface{}{
+				"worker": map[string]interface{}{
+					"template": map[string]interface{}{},
+				},
+			},
+		}
+
+		result := AddLabels(map[string]string{"foo": "bar"}, podTemplates, log)(obj)
+		labelsMap, found, err := unstructured.NestedStringMap(result.Object,
+			"spec", "worker", "template", "metadata", "labels")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(labelsMap).To(HaveKeyWithValue("foo", "bar"))
+	})
+
 })
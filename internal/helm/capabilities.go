@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// Capabilities are the cluster properties exposed to charts as .Capabilities
+type Capabilities struct {
+	KubeVersion *chartutil.KubeVersion
+	APIVersions []string
+}
+
+type capabilitiesCache struct {
+	mu           sync.RWMutex
+	capabilities *Capabilities
+}
+
+func (c *capabilitiesCache) get() (*Capabilities, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.capabilities, c.capabilities != nil
+}
+
+func (c *capabilitiesCache) set(capabilities *Capabilities) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capabilities = capabilities
+}
+
+// RefreshCapabilities discovers the server version and the served API versions.
+// A configured KubeVersion takes precedence over the discovered one and
+// configured API versions are added to the discovered ones.
+func (h *HelmClientImpl) RefreshCapabilities() error {
+	discovered, err := h.options.discoverCapabilities(h.options.RestConfig)
+	if err != nil {
+		return err
+	}
+	apiVersions := make([]string, 0, len(discovered.APIVersions)+len(h.options.APIVersions))
+	apiVersions = append(apiVersions, discovered.APIVersions...)
+	apiVersions = append(apiVersions, h.options.APIVersions...)
+	capabilities := &Capabilities{
+		KubeVersion: discovered.KubeVersion,
+		APIVersions: apiVersions,
+	}
+	if h.options.KubeVersion != nil {
+		capabilities.KubeVersion = h.options.KubeVersion
+	}
+	h.capabilities.set(capabilities)
+	return nil
+}
+
+// RunCapabilitiesRefresh periodically refreshes the capabilities until the context is done
+func (h *HelmClientImpl) RunCapabilitiesRefresh(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := h.RefreshCapabilities(); err != nil {
+				h.options.Log.Error(err, "Failed to refresh cluster capabilities")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// getCapabilities returns the last discovered capabilities, discovering them on first use.
+// When discovery is not possible the configured or Helm default capabilities are used.
+func (h *HelmClientImpl) getCapabilities() *Capabilities {
+	if capabilities, found := h.capabilities.get(); found {
+		return capabilities
+	}
+	if err := h.RefreshCapabilities(); err != nil {
+		h.options.Log.Error(err, "Failed to discover cluster capabilities, using defaults")
+		kubeVersion := h.options.KubeVersion
+		if kubeVersion == nil {
+			kubeVersion = &chartutil.DefaultCapabilities.KubeVersion
+		}
+		return &Capabilities{KubeVersion: kubeVersion, APIVersions: h.options.APIVersions}
+	}
+	capabilities, _ := h.capabilities.get()
+	return capabilities
+}
+
+func discoverCapabilities(cfg *rest.Config) (*Capabilities, error) {
+	disc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	serverVersion, err := disc.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	apiVersions, err := action.GetVersionSet(disc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API versions: %w", err)
+	}
+	return &Capabilities{
+		KubeVersion: &chartutil.KubeVersion{
+			Version: serverVersion.GitVersion,
+			Major:   serverVersion.Major,
+			Minor:   serverVersion.Minor,
+		},
+		APIVersions: apiVersions,
+	}, nil
+}
+
+// staticCapabilitiesForTests does not contact any cluster, Helm adds its default API versions when templating
+func staticCapabilitiesForTests(cfg *rest.Config) (*Capabilities, error) {
+	return &Capabilities{
+		KubeVersion: &chartutil.DefaultCapabilities.KubeVersion,
+	}, nil
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"github.com/cockroachdb/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/client-go/rest"
+)
+
+var _ = Describe("HelmClient capabilities", func() {
+	var (
+		client      *HelmClientImpl
+		discovered  *Capabilities
+		discoverErr error
+	)
+
+	BeforeEach(func() {
+		discovered = &Capabilities{
+			KubeVersion: &chartutil.KubeVersion{Version: "v1.31.2", Major: "1", Minor: "31"},
+			APIVersions: []string{"monitoring.coreos.com/v1"},
+		}
+		discoverErr = nil
+		client = newMirrorClient("")
+		client.options.KubeVersion = nil
+		client.options.discoverCapabilities = func(cfg *rest.Config) (*Capabilities, error) {
+			return discovered, discoverErr
+		}
+	})
+
+	It("should use discovered capabilities", func() {
+		capabilities := client.getCapabilities()
+		Expect(capabilities.KubeVersion.Version).To(Equal("v1.31.2"))
+		Expect(capabilities.APIVersions).To(ConsistOf("monitoring.coreos.com/v1"))
+	})
+
+	It("should prefer configured kube version and add configured API versions", func() {
+		client.options.KubeVersion = &chartutil.KubeVersion{Version: "v1.28.0", Major: "1", Minor: "28"}
+		client.options.APIVersions = []string{"example.com/v1"}
+
+		capabilities := client.getCapabilities()
+		Expect(capabilities.KubeVersion.Version).To(Equal("v1.28.0"))
+		Expect(capabilities.APIVersions).To(ConsistOf("monitoring.coreos.com/v1", "example.com/v1"))
+	})
+
+	It("should keep last discovered capabilities when refresh fails", func() {
+		Expect(client.RefreshCapabilities()).To(Succeed())
+
+		discoverErr = errors.New("unreachable")
+		Expect(client.RefreshCapabilities()).NotTo(Succeed())
+		Expect(client.getCapabilities().KubeVersion.Version).To(Equal("v1.31.2"))
+	})
+
+	It("should fall back to defaults when discovery is not possible", func() {
+		discoverErr = errors.New("unreachable")
+		Expect(client.getCapabilities().KubeVersion).To(Equal(&chartutil.DefaultCapabilities.KubeVersion))
+	})
+})
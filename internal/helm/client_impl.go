@@ -4,8 +4,13 @@
 package helm
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"time"
 
 	"net/url"
@@ -16,38 +21,74 @@ import (
 	"github.com/go-logr/logr"
 	helmclient "github.com/mittwald/go-helm-client"
 	"github.com/mittwald/go-helm-client/values"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/repo"
+	"hiro.io/anyapplication/internal/podtemplate"
+	"hiro.io/anyapplication/internal/restmapping"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/discovery"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/restmapper"
 	"sigs.k8s.io/yaml"
 )
 
 const (
 	defaultCachePath            = "/tmp/.helmcache"
 	defaultRepositoryConfigPath = "/tmp/.helmrepo"
+	repositoryIndexFile         = "index.yaml"
+	sourceCommentPrefix         = "# Source:"
+	unknownManifestSource       = "unknown template"
 )
 
 type HelmClientOptions struct {
-	RestConfig           *rest.Config
-	Debug                bool
-	Linting              bool
-	KubeVersion          *chartutil.KubeVersion
-	ClientId             string
-	Log                  logr.Logger
-	buildClusterScopeMap func(cfg *rest.Config) (map[schema.GroupVersionKind]bool, error)
+	RestConfig *rest.Config
+	Debug      bool
+	Linting    bool
+	// KubeVersion overrides the discovered server version when set
+	KubeVersion *chartutil.KubeVersion
+	// APIVersions are added to the discovered API versions
+	APIVersions []string
+	ClientId    string
+	// MirrorDir holds pre-seeded repositories, one directory per repository
+	// named after DeriveUniqueHelmRepoName, each with index.yaml and chart archives.
+	// Mirrored repositories and file:// repositories are resolved without network access.
+	MirrorDir string
+	Log       logr.Logger
+	// ScopeMapper resolves the scope of kinds, a discovery backed cache is created when not set
+	ScopeMapper ScopeMapper
+	// PodTemplates locates pod templates of workloads, built-in kinds are used when not set
+	PodTemplates *podtemplate.Registry
+	// PostProcessors are applied to resources of all charts after labels and namespace are set
+	PostProcessors       []PostProcessor
+	discoverCapabilities func(cfg *rest.Config) (*Capabilities, error)
+}
+
+// ScopeMapper provides a map where true = cluster-scoped, false = namespaced
+type ScopeMapper interface {
+	ClusterScopeMap() (map[schema.GroupVersionKind]bool, error)
 }
 
 type HelmClientImpl struct {
-	client  helmclient.Client
-	options *HelmClientOptions
+	client       helmclient.Client
+	options      *HelmClientOptions
+	capabilities *capabilitiesCache
 }
 
 func NewHelmClient(options *HelmClientOptions) (*HelmClientImpl, error) {
-	options.buildClusterScopeMap = buildGVKClusterScopeMap
+	options.discoverCapabilities = discoverCapabilities
+	if options.ScopeMapper == nil {
+		scopeMapper, err := restmapping.NewCachedMapper(options.RestConfig, options.Log)
+		if err != nil {
+			return nil, err
+		}
+		options.ScopeMapper = scopeMapper
+	}
 	if options.ClientId == "" {
 		clientId, err := RandClient()
 		if err != nil {
@@ -68,12 +109,14 @@ func NewHelmClient(options *HelmClientOptions) (*HelmClientImpl, error) {
 	}
 	client, err := helmclient.NewClientFromRestConf(&opts)
 
-	return &HelmClientImpl{client, options}, err
+	return &HelmClientImpl{client, options, &capabilitiesCache{}}, err
 }
 
 func NewTestClient(options *HelmClientOptions) (*HelmClientImpl, error) {
 	client, err := NewHelmClient(options)
-	options.buildClusterScopeMap = BuildStaticGVKClusterScopeMapForTests
+	scopes, _ := BuildStaticGVKClusterScopeMapForTests(options.RestConfig)
+	options.ScopeMapper = StaticScopeMapper(scopes)
+	options.discoverCapabilities = staticCapabilitiesForTests
 	return client, err
 }
 
@@ -87,9 +130,16 @@ type TemplateArgs struct {
 	ValuesYaml    string
 	Labels        map[string]string
 	UpgradeCRDs   bool
+	// Keyring enables provenance verification of the chart when set
+	Keyring *Keyring
+	// PostProcessors are applied to the resources of this chart after the client post processors
+	PostProcessors []PostProcessor
 }
 
 func (h *HelmClientImpl) AddOrUpdateChartRepo(repoURL string) (string, error) {
+	if _, isLocal := h.localRepositoryDir(repoURL); isLocal {
+		return DeriveUniqueHelmRepoName(repoURL)
+	}
 
 	chartRepo, err := h.addOrUpdateChartRepo(repoURL)
 	if err != nil {
@@ -118,38 +168,97 @@ func (h *HelmClientImpl) addOrUpdateChartRepo(repoURL string) (*repo.Entry, erro
 	return &chartRepo, nil
 }
 
+// SyncRepositories updates index files of remote repositories. Repositories which resolve
+// to the mirror or to a file:// url are served locally and are not updated.
 func (h *HelmClientImpl) SyncRepositories() error {
-	return h.client.UpdateChartRepos()
+	settings := h.client.GetSettings()
+	repoFile, err := repo.LoadFile(settings.RepositoryConfig)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return errors.Wrap(err, "Failed to load repository config")
+	}
+	var updateErr error
+	for _, entry := range repoFile.Repositories {
+		if _, isLocal := h.localRepositoryDir(entry.URL); isLocal {
+			h.options.Log.Info("Skipping update of local repository", "repository", entry.URL)
+			continue
+		}
+		if err := updateRepositoryIndex(entry, settings); err != nil {
+			updateErr = errors.CombineErrors(updateErr, errors.Wrapf(err, "Failed to update repository %s", entry.URL))
+		}
+	}
+	return updateErr
 }
 
-func (h *HelmClientImpl) FetchVersions(repoURL string, chartName string) ([]*semver.Version, error) {
-	if repoURL == "" {
-		return nil, errors.New("repoURL cannot be empty")
+func updateRepositoryIndex(entry *repo.Entry, settings *cli.EnvSettings) error {
+	if registry.IsOCI(entry.URL) {
+		return nil
 	}
-
-	entry, err := h.addOrUpdateChartRepo(repoURL)
+	chartRepo, err := repo.NewChartRepository(entry, getter.All(settings))
 	if err != nil {
-		return nil, err
+		return err
 	}
+	chartRepo.CachePath = settings.RepositoryCache
+	_, err = chartRepo.DownloadIndexFile()
+	return err
+}
 
-	chartRepo, err := repo.NewChartRepository(entry, h.client.GetProviders())
+// localRepositoryDir returns the directory serving the repository
+// if it is a file:// repository or it is pre-seeded in the mirror directory.
+func (h *HelmClientImpl) localRepositoryDir(repoURL string) (string, bool) {
+	u, err := url.Parse(repoURL)
 	if err != nil {
-		return nil, err
+		return "", false
 	}
-	chartRepo.CachePath = h.client.GetSettings().RepositoryCache
+	if u.Scheme == "file" {
+		return u.Path, true
+	}
+	if h.options.MirrorDir == "" {
+		return "", false
+	}
+	repoName, err := DeriveUniqueHelmRepoName(repoURL)
+	if err != nil {
+		return "", false
+	}
+	dir := filepath.Join(h.options.MirrorDir, repoName)
+	if _, err := os.Stat(filepath.Join(dir, repositoryIndexFile)); err != nil {
+		return "", false
+	}
+	return dir, true
+}
 
-	indexFile, err := chartRepo.DownloadIndexFile()
+// localChartPath resolves the chart archive of a specific version in a local repository.
+func localChartPath(dir string, chartName string, version string) (string, error) {
+	repoIndex, err := repo.LoadIndexFile(filepath.Join(dir, repositoryIndexFile))
 	if err != nil {
-		return nil, fmt.Errorf("looks like %q is not a valid chart repository or cannot be reached: %w", repoURL, err)
+		return "", errors.Wrapf(err, "Failed to load index of local repository %s", dir)
 	}
-	attempts := 10
-	for attempts > 0 {
-		data, err := os.ReadFile(indexFile)
-		if err == nil && len(data) != 0 {
-			break
+	chartVersion, err := repoIndex.Get(chartName, version)
+	if err != nil {
+		return "", errors.Wrapf(err, "Chart %s:%s not found in local repository %s", chartName, version, dir)
+	}
+	archive := fmt.Sprintf("%s-%s.tgz", chartName, chartVersion.Version)
+	if len(chartVersion.URLs) > 0 {
+		if u, err := url.Parse(chartVersion.URLs[0]); err == nil && u.Scheme != "http" && u.Scheme != "https" {
+			archive = u.Path
 		}
-		time.Sleep(100 * time.Millisecond)
-		attempts--
+	}
+	if !filepath.IsAbs(archive) {
+		archive = filepath.Join(dir, archive)
+	}
+	return archive, nil
+}
+
+func (h *HelmClientImpl) FetchVersions(repoURL string, chartName string) ([]*semver.Version, error) {
+	if repoURL == "" {
+		return nil, errors.New("repoURL cannot be empty")
+	}
+
+	indexFile, err := h.fetchIndexFile(repoURL)
+	if err != nil {
+		return nil, err
 	}
 
 	repoIndex, err := repo.LoadIndexFile(indexFile)
@@ -175,15 +284,54 @@ func (h *HelmClientImpl) FetchVersions(repoURL string, chartName string) ([]*sem
 	return nil, nil
 }
 
+// fetchIndexFile returns the path of the repository index. Local repositories are read in place,
+// remote index files are downloaded with a fallback to the previously downloaded copy.
+func (h *HelmClientImpl) fetchIndexFile(repoURL string) (string, error) {
+	if dir, isLocal := h.localRepositoryDir(repoURL); isLocal {
+		return filepath.Join(dir, repositoryIndexFile), nil
+	}
+
+	entry, err := h.addOrUpdateChartRepo(repoURL)
+	if err != nil {
+		return "", err
+	}
+
+	chartRepo, err := repo.NewChartRepository(entry, h.client.GetProviders())
+	if err != nil {
+		return "", err
+	}
+	chartRepo.CachePath = h.client.GetSettings().RepositoryCache
+
+	indexFile, err := chartRepo.DownloadIndexFile()
+	if err != nil {
+		cachedIndexFile := filepath.Join(chartRepo.CachePath, helmpath.CacheIndexFile(entry.Name))
+		if _, statErr := os.Stat(cachedIndexFile); statErr == nil {
+			h.options.Log.Info("Using cached repository index", "repoURL", repoURL, "reason", err.Error())
+			return cachedIndexFile, nil
+		}
+		return "", fmt.Errorf("looks like %q is not a valid chart repository or cannot be reached: %w", repoURL, err)
+	}
+	attempts := 10
+	for attempts > 0 {
+		data, err := os.ReadFile(indexFile)
+		if err == nil && len(data) != 0 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+		attempts--
+	}
+	return indexFile, nil
+}
+
 func (h *HelmClientImpl) Template(args *TemplateArgs) (string, error) {
 
-	repoName, err := h.AddOrUpdateChartRepo(args.RepoUrl)
+	chartName, err := h.resolveChartName(args)
 	if err != nil {
 		return "", err
 	}
 	chartSpec := helmclient.ChartSpec{
 		ReleaseName: args.ReleaseName,
-		ChartName:   repoName + "/" + args.ChartName,
+		ChartName:   chartName,
 		Version:     args.Version,
 		Namespace:   args.Namespace,
 		UpgradeCRDs: args.UpgradeCRDs,
@@ -200,28 +348,98 @@ func (h *HelmClientImpl) Template(args *TemplateArgs) (string, error) {
 		chartSpec.ValuesOptions = args.ValuesOptions
 	}
 
-	options := &helmclient.HelmTemplateOptions{
-		KubeVersion: h.options.KubeVersion,
-		APIVersions: []string{},
+	helmChart, err := h.loadChart(&chartSpec)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to template chart")
 	}
-
-	chartBytes, err := h.client.TemplateChart(&chartSpec, options)
+	chartValues, err := h.validateValues(helmChart, &chartSpec)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := h.templateChart(helmChart, chartValues, &chartSpec)
 	if err != nil {
 		return "", errors.Wrap(err, "Failed to template chart")
 	}
-	manifest := string(chartBytes)
 
-	isClusterScope, err := h.options.buildClusterScopeMap(h.options.RestConfig)
+	isClusterScope, err := h.options.ScopeMapper.ClusterScopeMap()
 	if err != nil {
 		return "", errors.Wrap(err, "Unable to build resource map and discover cluster-wide resources")
 	}
 	// Go Helm Client does not support extra labels and namespace post processing
 	// This is post processing step to fix that
-	return PostProcessManifests(
-		manifest,
-		AddLabels(args.Labels, h.options.Log),
+	postProcessors := []PostProcessor{
+		AddLabels(args.Labels, h.options.PodTemplates, h.options.Log),
 		AddNamespace(args.Namespace, isClusterScope, h.options.Log),
-	)
+	}
+	postProcessors = append(postProcessors, h.options.PostProcessors...)
+	postProcessors = append(postProcessors, args.PostProcessors...)
+	return PostProcessManifests(manifest, postProcessors...)
+}
+
+// loadChart loads the chart of the spec once, it is validated and templated from memory
+func (h *HelmClientImpl) loadChart(chartSpec *helmclient.ChartSpec) (*chart.Chart, error) {
+	helmChart, _, err := h.client.GetChart(chartSpec.ChartName, &action.ChartPathOptions{Version: chartSpec.Version})
+	if err != nil {
+		return nil, err
+	}
+	if helmChart.Metadata.Type != "" && helmChart.Metadata.Type != "application" {
+		return nil, errors.Newf("Chart %s has an unsupported type %s and is not installable",
+			helmChart.Metadata.Name, helmChart.Metadata.Type)
+	}
+	if dependencies := helmChart.Metadata.Dependencies; dependencies != nil {
+		if err := action.CheckDependencies(helmChart, dependencies); err != nil {
+			return nil, err
+		}
+	}
+	return helmChart, nil
+}
+
+// templateChart renders the loaded chart client-side like 'helm template' does
+func (h *HelmClientImpl) templateChart(
+	helmChart *chart.Chart,
+	chartValues map[string]any,
+	chartSpec *helmclient.ChartSpec,
+) (string, error) {
+	install := action.NewInstall(&action.Configuration{Log: func(string, ...interface{}) {}})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = true
+	install.ReleaseName = chartSpec.ReleaseName
+	install.Namespace = chartSpec.Namespace
+	install.Version = chartSpec.Version
+	install.Labels = chartSpec.Labels
+
+	capabilities := h.getCapabilities()
+	install.KubeVersion = capabilities.KubeVersion
+	install.APIVersions = capabilities.APIVersions
+
+	release, err := install.Run(helmChart, chartValues)
+	if err != nil {
+		return "", err
+	}
+	var manifests bytes.Buffer
+	fmt.Fprintln(&manifests, strings.TrimSpace(release.Manifest))
+	for _, hook := range release.Hooks {
+		fmt.Fprintf(&manifests, "---\n# Source: %s\n%s\n", hook.Path, hook.Manifest)
+	}
+	return manifests.String(), nil
+}
+
+// resolveChartName returns the archive path for charts of local repositories or charts requiring
+// verification and the repository qualified chart name otherwise.
+func (h *HelmClientImpl) resolveChartName(args *TemplateArgs) (string, error) {
+	if args.Keyring != nil {
+		return h.verifiedChartPath(args)
+	}
+	if dir, isLocal := h.localRepositoryDir(args.RepoUrl); isLocal {
+		return localChartPath(dir, args.ChartName, args.Version)
+	}
+	repoName, err := h.AddOrUpdateChartRepo(args.RepoUrl)
+	if err != nil {
+		return "", err
+	}
+	return repoName + "/" + args.ChartName, nil
 }
 
 func DeriveUniqueHelmRepoName(repoURL string) (string, error) {
@@ -238,7 +456,7 @@ func DeriveUniqueHelmRepoName(repoURL string) (string, error) {
 }
 
 // Go Helm Client does not namespace postprocessing
-func AddNamespace(namespace string, isClusterScopeRegistry map[schema.GroupVersionKind]bool, log logr.Logger) func(obj unstructured.Unstructured) unstructured.Unstructured {
+func AddNamespace(namespace string, isClusterScopeRegistry map[schema.GroupVersionKind]bool, log logr.Logger) PostProcessor {
 	return func(obj unstructured.Unstructured) unstructured.Unstructured {
 		var updateNamespace = false
 		gvk := obj.GroupVersionKind()
@@ -258,7 +476,8 @@ func AddNamespace(namespace string, isClusterScopeRegistry map[schema.GroupVersi
 	}
 }
 
-func AddLabels(newLabels map[string]string, log logr.Logger) func(obj unstructured.Unstructured) unstructured.Unstructured {
+// AddLabels sets the labels on the object and on the pod template of workloads known to the registry
+func AddLabels(newLabels map[string]string, podTemplates *podtemplate.Registry, log logr.Logger) PostProcessor {
 	return func(obj unstructured.Unstructured) unstructured.Unstructured {
 		// Merge new labels into existing ones
 		labels := obj.GetLabels()
@@ -269,22 +488,24 @@ func AddLabels(newLabels map[string]string, log logr.Logger) func(obj unstructur
 			labels[k] = v
 		}
 		obj.SetLabels(labels)
-		if obj.GetKind() == "Deployment" || obj.GetKind() == "StatefulSet" || obj.GetKind() == "DaemonSet" || obj.GetKind() == "Job" {
-			// add labels to the spec template
-			_, found, err := unstructured.NestedMap(obj.Object, "spec", "template")
+		templatePath, isWorkload := podTemplates.TemplatePath(&obj)
+		if isWorkload && len(templatePath) > 0 {
+			// add labels to the pod template
+			metadataPath := append(append(make([]string, 0, len(templatePath)+1), templatePath...), "metadata")
+			_, found, err := unstructured.NestedMap(obj.Object, templatePath...)
 			if err != nil {
-				log.Error(err, "Failed to get spec template",
+				log.Error(err, "Failed to get pod template",
 					"kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
 			}
 			if found {
-				_, found, _ := unstructured.NestedMap(obj.Object, "spec", "template", "metadata")
+				_, found, _ := unstructured.NestedMap(obj.Object, metadataPath...)
 				if !found {
-					if err := unstructured.SetNestedMap(obj.Object, make(map[string]interface{}), "spec", "template", "metadata"); err != nil {
+					if err := unstructured.SetNestedMap(obj.Object, make(map[string]interface{}), metadataPath...); err != nil {
 						log.Error(err, "Failed to set empty metadata",
 							"kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
 					}
 				}
-				meta, found, _ := unstructured.NestedMap(obj.Object, "spec", "template", "metadata")
+				meta, found, _ := unstructured.NestedMap(obj.Object, metadataPath...)
 				if found {
 					if meta["labels"] == nil {
 						meta["labels"] = make(map[string]interface{})
@@ -292,12 +513,12 @@ func AddLabels(newLabels map[string]string, log logr.Logger) func(obj unstructur
 					for k, v := range newLabels {
 						meta["labels"].(map[string]interface{})[k] = v
 					}
-					if err := unstructured.SetNestedMap(obj.Object, meta, "spec", "template", "metadata"); err != nil {
-						log.Error(err, "Failed to set spec template metadata",
+					if err := unstructured.SetNestedMap(obj.Object, meta, metadataPath...); err != nil {
+						log.Error(err, "Failed to set pod template metadata",
 							"kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
 					}
 				} else {
-					log.Error(err, "Failed to set labels to template spec",
+					log.Error(err, "Failed to set labels to pod template",
 						"kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
 				}
 			}
@@ -307,78 +528,76 @@ func AddLabels(newLabels map[string]string, log logr.Logger) func(obj unstructur
 	}
 }
 
-// This is post processing step to fix custom labels and namespaces
-func PostProcessManifests(manifest string, funcs ...func(obj unstructured.Unstructured) unstructured.Unstructured) (string, error) {
-	docs := strings.Split(manifest, "---")
+// This is post processing step to fix custom labels and namespaces.
+// Documents of List kinds are flattened into their items, documents that cannot be parsed
+// fail the whole manifest and the error names the template they were rendered from.
+func PostProcessManifests(manifest string, funcs ...PostProcessor) (string, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(manifest)))
 	output := make([]string, 0, 10)
 
-	for _, doc := range docs {
-		doc = strings.TrimSpace(doc)
-		if doc == "" {
-			continue
-		}
-
-		var obj unstructured.Unstructured
-		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
-			// Keep unparseable docs untouched (e.g. comments or notes)
-			output = append(output, doc)
-			continue
-		}
-		apiVersion := obj.GetAPIVersion()
-		kind := obj.GetKind()
-		if apiVersion == "" && kind == "" {
-			output = append(output, doc)
-			continue
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
 		}
-
-		for _, postprocessor := range funcs {
-			obj = postprocessor(obj)
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to read manifest")
 		}
+		source := manifestSource(doc)
 
-		// Marshal back to YAML
-		modifiedYAML, err := yaml.Marshal(obj.Object)
+		objects, err := decodeManifest(doc)
 		if err != nil {
-			return "", err
+			return "", errors.Wrapf(err, "Failed to parse manifest from %s", source)
 		}
 
-		output = append(output, string(modifiedYAML))
+		for _, obj := range objects {
+			for _, postprocessor := range funcs {
+				obj = postprocessor(obj)
+			}
+
+			// Marshal back to YAML
+			modifiedYAML, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return "", errors.Wrapf(err, "Failed to marshal manifest from %s", source)
+			}
+			output = append(output, string(modifiedYAML))
+		}
 	}
 
 	return strings.Join(output, "---\n"), nil
 }
 
-// BuildGVKClusterScopeMap returns a map where true = cluster-scoped, false = namespaced.
-func buildGVKClusterScopeMap(cfg *rest.Config) (map[schema.GroupVersionKind]bool, error) {
-	disc, err := discovery.NewDiscoveryClientForConfig(cfg)
+// decodeManifest returns the objects of a single YAML document, documents with comments only yield nothing
+func decodeManifest(doc []byte) ([]unstructured.Unstructured, error) {
+	jsonDoc, err := yaml.YAMLToJSON(doc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+		return nil, err
 	}
-
-	gr, err := restmapper.GetAPIGroupResources(disc)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API group resources: %w", err)
+	if string(bytes.TrimSpace(jsonDoc)) == "null" {
+		return nil, nil
 	}
 
-	result := make(map[schema.GroupVersionKind]bool)
+	var obj unstructured.Unstructured
+	if err := obj.UnmarshalJSON(jsonDoc); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(obj.GetKind(), "List") || !obj.IsList() {
+		return []unstructured.Unstructured{obj}, nil
+	}
 
-	for _, groupResources := range gr {
+	list, err := obj.ToList()
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
 
-		// groupResources.VersionedResources is: map[groupVersionString][]APIResource
-		for version, resources := range groupResources.VersionedResources {
-			gv := schema.GroupVersion{
-				Group:   groupResources.Group.Name, // core="" , others correct
-				Version: version,
-			}
-			for _, r := range resources {
-				// skip subresources
-				if strings.Contains(r.Name, "/") {
-					continue
-				}
-				gvk := gv.WithKind(r.Kind)
-				result[gvk] = !r.Namespaced
-			}
+// manifestSource returns the template name from the "# Source:" comment Helm puts in front of every document
+func manifestSource(doc []byte) string {
+	for _, line := range strings.Split(string(doc), "\n") {
+		if source, found := strings.CutPrefix(strings.TrimSpace(line), sourceCommentPrefix); found {
+			return strings.TrimSpace(source)
 		}
 	}
-
-	return result, nil
+	return unknownManifestSource
 }
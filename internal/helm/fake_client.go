@@ -41,9 +41,16 @@ func (c *FakeHelmClient) FetchVersions(repoURL string, chartName string) ([]*sem
 	}, nil
 }
 
+// StaticScopeMapper serves a fixed scope map
+type StaticScopeMapper map[schema.GroupVersionKind]bool
+
+func (m StaticScopeMapper) ClusterScopeMap() (map[schema.GroupVersionKind]bool, error) {
+	return m, nil
+}
+
 // BuildGVKClusterScopeMapStatic returns a static map of major Kubernetes GVKs → cluster-scoped (true) or namespaced (false).
 // this map is not complete and is used for testing purposes
-// the one used in production in client_impl.go
+// the one used in production is restmapping.CachedMapper
 func BuildStaticGVKClusterScopeMapForTests(cfg *rest.Config) (map[schema.GroupVersionKind]bool, error) {
 	result := make(map[schema.GroupVersionKind]bool)
 
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+	"k8s.io/client-go/rest"
+)
+
+// seedRepository packages a scaffold chart for each version into repoDir and indexes it
+func seedRepository(repoDir string, versions ...string) {
+	Expect(os.MkdirAll(repoDir, 0o750)).To(Succeed())
+	for _, version := range versions {
+		workDir := GinkgoT().TempDir()
+		chartDir, err := chartutil.Create("mirrored", workDir)
+		Expect(err).NotTo(HaveOccurred())
+		chart, err := loader.Load(chartDir)
+		Expect(err).NotTo(HaveOccurred())
+		chart.Metadata.Version = version
+		_, err = chartutil.Save(chart, repoDir)
+		Expect(err).NotTo(HaveOccurred())
+	}
+	index, err := repo.IndexDirectory(repoDir, "")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(index.WriteFile(filepath.Join(repoDir, repositoryIndexFile), 0o600)).To(Succeed())
+}
+
+func newMirrorClient(mirrorDir string) *HelmClientImpl {
+	options := &HelmClientOptions{
+		RestConfig: &rest.Config{Host: "https://localhost:6443"},
+		KubeVersion: &chartutil.KubeVersion{
+			Version: "v1.23.10",
+			Major:   "1",
+			Minor:   "23",
+		},
+		MirrorDir: mirrorDir,
+		Log:       logr.Discard(),
+	}
+	client, err := NewTestClient(options)
+	Expect(err).NotTo(HaveOccurred())
+	settings := client.client.GetSettings()
+	DeferCleanup(os.RemoveAll, settings.RepositoryConfig)
+	DeferCleanup(os.RemoveAll, settings.RepositoryCache)
+	return client
+}
+
+var _ = Describe("HelmClient mirror", func() {
+	const mirroredRepoUrl = "https://charts.example.com/stable"
+
+	var (
+		client    *HelmClientImpl
+		mirrorDir string
+	)
+
+	BeforeEach(func() {
+		mirrorDir = GinkgoT().TempDir()
+		repoName, err := DeriveUniqueHelmRepoName(mirroredRepoUrl)
+		Expect(err).NotTo(HaveOccurred())
+		seedRepository(filepath.Join(mirrorDir, repoName), "1.0.0", "1.1.0")
+		client = newMirrorClient(mirrorDir)
+	})
+
+	It("should fetch versions from mirrored repository", func() {
+		repoName, err := client.AddOrUpdateChartRepo(mirroredRepoUrl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(repoName).To(Equal("charts-example-com-stable"))
+
+		versions, err := client.FetchVersions(mirroredRepoUrl, "mirrored")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions).To(HaveLen(2))
+		Expect(client.SyncRepositories()).To(Succeed())
+	})
+
+	It("should template chart from mirrored repository", func() {
+		manifest, err := client.Template(&TemplateArgs{
+			ReleaseName: "test-release",
+			RepoUrl:     mirroredRepoUrl,
+			ChartName:   "mirrored",
+			Namespace:   "default",
+			Version:     "1.1.0",
+			Labels:      map[string]string{"dcp.hiro.io/test": "dcp"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest).To(ContainSubstring("helm.sh/chart: mirrored-1.1.0"))
+		Expect(manifest).To(ContainSubstring("dcp.hiro.io/test: dcp"))
+	})
+
+	It("should template chart from file repository", func() {
+		repoDir := filepath.Join(GinkgoT().TempDir(), "local")
+		seedRepository(repoDir, "2.0.0")
+
+		versions, err := client.FetchVersions("file://"+repoDir, "mirrored")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versions).To(HaveLen(1))
+
+		manifest, err := client.Template(&TemplateArgs{
+			ReleaseName: "test-release",
+			RepoUrl:     "file://" + repoDir,
+			ChartName:   "mirrored",
+			Namespace:   "default",
+			Version:     "2.0.0",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest).To(ContainSubstring("helm.sh/chart: mirrored-2.0.0"))
+	})
+
+	It("should fail for versions missing in mirror", func() {
+		_, err := client.Template(&TemplateArgs{
+			ReleaseName: "test-release",
+			RepoUrl:     mirroredRepoUrl,
+			ChartName:   "mirrored",
+			Namespace:   "default",
+			Version:     "3.0.0",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should sync mirrored repositories offline and fail for unreachable remote repositories", func() {
+		remoteDir := filepath.Join(GinkgoT().TempDir(), "remote")
+		seedRepository(remoteDir, "1.0.0")
+		server := httptest.NewServer(http.FileServer(http.Dir(remoteDir)))
+		_, err := client.AddOrUpdateChartRepo(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.SyncRepositories()).To(Succeed())
+
+		server.Close()
+		err = client.SyncRepositories()
+		Expect(err).To(MatchError(ContainSubstring("Failed to update repository " + server.URL)))
+
+		repoName, err := DeriveUniqueHelmRepoName(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		seedRepository(filepath.Join(mirrorDir, repoName), "1.0.0")
+		Expect(client.SyncRepositories()).To(Succeed())
+	})
+})
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"hiro.io/anyapplication/internal/podtemplate"
+)
+
+var _ = Describe("PostProcessManifests", func() {
+	labels := AddLabels(map[string]string{"dcp.hiro.io/instance-id": "test"}, podtemplate.DefaultRegistry(), logr.Discard())
+
+	It("should keep separators inside of values", func() {
+		manifest := `---
+# Source: test/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-cm
+data:
+  config.yaml: |
+    first: 1
+    ---
+    second: 2
+  separator: "---"
+---
+# Source: test/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: test-svc
+`
+		result, err := PostProcessManifests(manifest, labels)
+		Expect(err).NotTo(HaveOccurred())
+
+		docs := splitManifest(result)
+		Expect(docs).To(HaveLen(2))
+		Expect(docs[0]["data"]).To(Equal(map[string]any{
+			"config.yaml": "first: 1\n---\nsecond: 2\n",
+			"separator":   "---",
+		}))
+		Expect(docs[1]["metadata"]).To(HaveKeyWithValue("labels", map[string]any{"dcp.hiro.io/instance-id": "test"}))
+	})
+
+	It("should flatten list kinds", func() {
+		manifest := `# Source: test/templates/list.yaml
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: first
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: second
+`
+		result, err := PostProcessManifests(manifest, labels)
+		Expect(err).NotTo(HaveOccurred())
+
+		docs := splitManifest(result)
+		Expect(docs).To(HaveLen(2))
+		Expect(docs[0]["kind"]).To(Equal("ConfigMap"))
+		Expect(docs[1]["kind"]).To(Equal("Secret"))
+		Expect(docs[1]["metadata"]).To(HaveKeyWithValue("labels", map[string]any{"dcp.hiro.io/instance-id": "test"}))
+	})
+
+	It("should skip documents with comments only", func() {
+		manifest := `---
+# Source: test/templates/empty.yaml
+---
+# Source: test/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-cm
+`
+		result, err := PostProcessManifests(manifest, labels)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(splitManifest(result)).To(HaveLen(1))
+	})
+
+	It("should fail with template name on unparseable document", func() {
+		manifest := `---
+# Source: test/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-cm
+---
+# Source: test/templates/broken.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: [broken
+`
+		_, err := PostProcessManifests(manifest, labels)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("test/templates/broken.yaml"))
+	})
+
+	It("should fail on documents without kind", func() {
+		manifest := `# Source: test/templates/notes.yaml
+message: not a kubernetes object
+`
+		_, err := PostProcessManifests(manifest, labels)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("test/templates/notes.yaml"))
+	})
+})
+
+func splitManifest(manifest string) []map[string]any {
+	docs := make([]map[string]any, 0)
+	objects, err := kube.SplitYAML([]byte(manifest))
+	Expect(err).NotTo(HaveOccurred())
+	for _, obj := range objects {
+		docs = append(docs, obj.Object)
+	}
+	return docs
+}
@@ -0,0 +1,249 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"hiro.io/anyapplication/internal/config"
+	"hiro.io/anyapplication/internal/podtemplate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	defaultRegistry       = "docker.io"
+	hostnameLabel         = "kubernetes.io/hostname"
+	nodeAffinitySeparator = "="
+)
+
+// PostProcessor modifies a rendered object before it is handed over for synchronization
+type PostProcessor func(obj unstructured.Unstructured) unstructured.Unstructured
+
+// RegistryMirror replaces the registry of matching images by the mirror
+type RegistryMirror struct {
+	// Registry is the registry to replace, all registries are replaced when empty
+	Registry string
+	Mirror   string
+}
+
+var containerFields = []string{"initContainers", "containers", "ephemeralContainers"}
+
+// NewPostProcessors builds the configured post processors applied to all rendered charts of the zone
+func NewPostProcessors(
+	cfg *config.PostProcessingConfig,
+	zoneId string,
+	podTemplates *podtemplate.Registry,
+	log logr.Logger,
+) []PostProcessor {
+	postProcessors := make([]PostProcessor, 0)
+	if len(cfg.Annotations) > 0 {
+		postProcessors = append(postProcessors, AddAnnotations(cfg.Annotations, log))
+	}
+	mirrors := make([]RegistryMirror, 0)
+	for _, mirror := range cfg.ImageMirrors {
+		if mirror.Zone == "" || mirror.Zone == zoneId {
+			mirrors = append(mirrors, RegistryMirror{Registry: mirror.Registry, Mirror: mirror.Mirror})
+		}
+	}
+	if len(mirrors) > 0 {
+		postProcessors = append(postProcessors, RewriteImageRegistry(mirrors, podTemplates, log))
+	}
+	if len(cfg.Tolerations) > 0 {
+		tolerations := make([]corev1.Toleration, 0, len(cfg.Tolerations))
+		for _, toleration := range cfg.Tolerations {
+			tolerations = append(tolerations, corev1.Toleration{
+				Key:               toleration.Key,
+				Operator:          corev1.TolerationOperator(toleration.Operator),
+				Value:             toleration.Value,
+				Effect:            corev1.TaintEffect(toleration.Effect),
+				TolerationSeconds: toleration.TolerationSeconds,
+			})
+		}
+		postProcessors = append(postProcessors, AddTolerations(tolerations, podTemplates, log))
+	}
+	if cfg.PriorityClassName != "" {
+		postProcessors = append(postProcessors, SetPriorityClass(cfg.PriorityClassName, podTemplates, log))
+	}
+	return postProcessors
+}
+
+func AddAnnotations(newAnnotations map[string]string, log logr.Logger) PostProcessor {
+	return func(obj unstructured.Unstructured) unstructured.Unstructured {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		for k, v := range newAnnotations {
+			annotations[k] = v
+		}
+		obj.SetAnnotations(annotations)
+		return obj
+	}
+}
+
+// RewriteImageRegistry pulls images of all containers from the first matching registry mirror
+func RewriteImageRegistry(mirrors []RegistryMirror, podTemplates *podtemplate.Registry, log logr.Logger) PostProcessor {
+	return updatePodSpec(podTemplates, log, func(podSpec map[string]interface{}) {
+		for _, field := range containerFields {
+			containers, found, _ := unstructured.NestedSlice(podSpec, field)
+			if !found {
+				continue
+			}
+			for _, container := range containers {
+				container, ok := container.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				image, ok := container["image"].(string)
+				if !ok || image == "" {
+					continue
+				}
+				container["image"] = mirrorImage(image, mirrors)
+			}
+			podSpec[field] = containers
+		}
+	})
+}
+
+// AddNodeAffinity requires pods to be scheduled to the given nodes.
+// Entries in the form key=value select nodes by label, other entries are node names.
+// The requirements are added to every existing node selector term of the chart.
+func AddNodeAffinity(nodeAffinity []string, podTemplates *podtemplate.Registry, log logr.Logger) PostProcessor {
+	expressions := nodeSelectorExpressions(nodeAffinity)
+
+	return updatePodSpec(podTemplates, log, func(podSpec map[string]interface{}) {
+		path := []string{"affinity", "nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms"}
+		terms, _, _ := unstructured.NestedSlice(podSpec, path...)
+		if len(terms) == 0 {
+			terms = []interface{}{map[string]interface{}{}}
+		}
+		for _, term := range terms {
+			term, ok := term.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			matchExpressions, _, _ := unstructured.NestedSlice(term, "matchExpressions")
+			term["matchExpressions"] = append(matchExpressions, runtime.DeepCopyJSONValue(expressions).([]interface{})...)
+		}
+		if err := unstructured.SetNestedSlice(podSpec, terms, path...); err != nil {
+			log.Error(err, "Failed to set node affinity")
+		}
+	})
+}
+
+// AddTolerations adds the tolerations missing in pod specs
+func AddTolerations(tolerations []corev1.Toleration, podTemplates *podtemplate.Registry, log logr.Logger) PostProcessor {
+	newTolerations := make([]interface{}, 0, len(tolerations))
+	for _, toleration := range tolerations {
+		value, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&toleration)
+		if err != nil {
+			log.Error(err, "Failed to convert toleration", "toleration", toleration.Key)
+			continue
+		}
+		newTolerations = append(newTolerations, value)
+	}
+
+	return updatePodSpec(podTemplates, log, func(podSpec map[string]interface{}) {
+		existing, _, _ := unstructured.NestedSlice(podSpec, "tolerations")
+		for _, toleration := range newTolerations {
+			present := false
+			for _, current := range existing {
+				if reflect.DeepEqual(current, toleration) {
+					present = true
+					break
+				}
+			}
+			if !present {
+				existing = append(existing, runtime.DeepCopyJSONValue(toleration))
+			}
+		}
+		podSpec["tolerations"] = existing
+	})
+}
+
+// SetPriorityClass sets the priority class of pods which do not define one
+func SetPriorityClass(priorityClassName string, podTemplates *podtemplate.Registry, log logr.Logger) PostProcessor {
+	return updatePodSpec(podTemplates, log, func(podSpec map[string]interface{}) {
+		if current, _, _ := unstructured.NestedString(podSpec, "priorityClassName"); current == "" {
+			podSpec["priorityClassName"] = priorityClassName
+		}
+	})
+}
+
+func updatePodSpec(podTemplates *podtemplate.Registry, log logr.Logger, update func(podSpec map[string]interface{})) PostProcessor {
+	return func(obj unstructured.Unstructured) unstructured.Unstructured {
+		path, supported := podTemplates.PodSpecPath(&obj)
+		if !supported {
+			return obj
+		}
+		podSpec, found, err := unstructured.NestedMap(obj.Object, path...)
+		if err != nil || !found {
+			return obj
+		}
+		update(podSpec)
+		if err := unstructured.SetNestedMap(obj.Object, podSpec, path...); err != nil {
+			log.Error(err, "Failed to set pod spec",
+				"kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
+		}
+		return obj
+	}
+}
+
+func nodeSelectorExpressions(nodeAffinity []string) []interface{} {
+	keys := make([]string, 0)
+	values := make(map[string][]interface{})
+	for _, entry := range nodeAffinity {
+		key, value, isLabel := strings.Cut(entry, nodeAffinitySeparator)
+		if !isLabel {
+			key, value = hostnameLabel, entry
+		}
+		if _, exists := values[key]; !exists {
+			keys = append(keys, key)
+		}
+		values[key] = append(values[key], value)
+	}
+
+	expressions := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		expressions = append(expressions, map[string]interface{}{
+			"key":      key,
+			"operator": string(corev1.NodeSelectorOpIn),
+			"values":   values[key],
+		})
+	}
+	return expressions
+}
+
+func mirrorImage(image string, mirrors []RegistryMirror) string {
+	registry, repository := splitImage(image)
+	for _, mirror := range mirrors {
+		mirrorHost, _, _ := strings.Cut(mirror.Mirror, "/")
+		if registry == mirrorHost {
+			return image
+		}
+		if mirror.Registry == "" || mirror.Registry == registry {
+			return strings.TrimSuffix(mirror.Mirror, "/") + "/" + repository
+		}
+	}
+	return image
+}
+
+// splitImage returns the registry of an image reference and the remainder, docker hub images are normalized
+func splitImage(image string) (string, string) {
+	registry, repository, found := strings.Cut(image, "/")
+	if !found || !(strings.ContainsAny(registry, ".:") || registry == "localhost") {
+		registry, repository = defaultRegistry, image
+	}
+	if registry == "index.docker.io" {
+		registry = defaultRegistry
+	}
+	if registry == defaultRegistry && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return registry, repository
+}
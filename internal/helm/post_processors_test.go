@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"hiro.io/anyapplication/internal/config"
+	"hiro.io/anyapplication/internal/podtemplate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newDeployment(podSpec map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "test-deploy"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{"spec": podSpec},
+		},
+	}}
+}
+
+func podSpecOf(obj unstructured.Unstructured) map[string]interface{} {
+	podSpec, found, err := unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+	Expect(err).NotTo(HaveOccurred())
+	Expect(found).To(BeTrue())
+	return podSpec
+}
+
+var _ = Describe("PostProcessors", func() {
+	var (
+		log logr.Logger
+	)
+
+	BeforeEach(func() {
+		log = logr.Discard()
+	})
+
+	It("should add annotations", func() {
+		obj := unstructured.Unstructured{}
+		obj.SetKind("ConfigMap")
+		obj.SetName("test-cm")
+		obj.SetAnnotations(map[string]string{"existing": "value"})
+
+		result := AddAnnotations(map[string]string{"dcp.hiro.io/zone": "zone-a"}, log)(obj)
+		Expect(result.GetAnnotations()).To(Equal(map[string]string{
+			"existing":         "value",
+			"dcp.hiro.io/zone": "zone-a",
+		}))
+	})
+
+	It("should rewrite images to registry mirrors", func() {
+		obj := newDeployment(map[string]interface{}{
+			"initContainers": []interface{}{
+				map[string]interface{}{"name": "init", "image": "busybox:1.36"},
+			},
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "ghcr.io/example/app:1.0"},
+				map[string]interface{}{"name": "sidecar", "image": "quay.io/example/sidecar:2.0"},
+				map[string]interface{}{"name": "mirrored", "image": "mirror.zone-a:5000/library/nginx"},
+			},
+		})
+		mirrors := []RegistryMirror{
+			{Registry: "ghcr.io", Mirror: "mirror.zone-a:5000/ghcr"},
+			{Registry: "docker.io", Mirror: "mirror.zone-a:5000"},
+		}
+
+		podSpec := podSpecOf(RewriteImageRegistry(mirrors, podtemplate.DefaultRegistry(), log)(obj))
+		Expect(podSpec["initContainers"]).To(Equal([]interface{}{
+			map[string]interface{}{"name": "init", "image": "mirror.zone-a:5000/library/busybox:1.36"},
+		}))
+		Expect(podSpec["containers"]).To(Equal([]interface{}{
+			map[string]interface{}{"name": "app", "image": "mirror.zone-a:5000/ghcr/example/app:1.0"},
+			map[string]interface{}{"name": "sidecar", "image": "quay.io/example/sidecar:2.0"},
+			map[string]interface{}{"name": "mirrored", "image": "mirror.zone-a:5000/library/nginx"},
+		}))
+	})
+
+	It("should add node affinity to existing node selector terms", func() {
+		obj := newDeployment(map[string]interface{}{
+			"affinity": map[string]interface{}{
+				"nodeAffinity": map[string]interface{}{
+					"requiredDuringSchedulingIgnoredDuringExecution": map[string]interface{}{
+						"nodeSelectorTerms": []interface{}{
+							map[string]interface{}{
+								"matchExpressions": []interface{}{
+									map[string]interface{}{"key": "arch", "operator": "In", "values": []interface{}{"amd64"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+
+		podSpec := podSpecOf(AddNodeAffinity([]string{"node-1", "node-2", "gpu=true"}, podtemplate.DefaultRegistry(), log)(obj))
+		terms, _, err := unstructured.NestedSlice(podSpec,
+			"affinity", "nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(terms).To(Equal([]interface{}{
+			map[string]interface{}{
+				"matchExpressions": []interface{}{
+					map[string]interface{}{"key": "arch", "operator": "In", "values": []interface{}{"amd64"}},
+					map[string]interface{}{"key": "kubernetes.io/hostname", "operator": "In", "values": []interface{}{"node-1", "node-2"}},
+					map[string]interface{}{"key": "gpu", "operator": "In", "values": []interface{}{"true"}},
+				},
+			},
+		}))
+	})
+
+	It("should add missing tolerations only", func() {
+		obj := newDeployment(map[string]interface{}{
+			"tolerations": []interface{}{
+				map[string]interface{}{"key": "dedicated", "operator": "Equal", "value": "dcp", "effect": "NoSchedule"},
+			},
+		})
+		tolerations := []corev1.Toleration{
+			{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "dcp", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "edge", Operator: corev1.TolerationOpExists},
+		}
+
+		podSpec := podSpecOf(AddTolerations(tolerations, podtemplate.DefaultRegistry(), log)(obj))
+		Expect(podSpec["tolerations"]).To(Equal([]interface{}{
+			map[string]interface{}{"key": "dedicated", "operator": "Equal", "value": "dcp", "effect": "NoSchedule"},
+			map[string]interface{}{"key": "edge", "operator": "Exists"},
+		}))
+	})
+
+	It("should set priority class when not defined", func() {
+		result := SetPriorityClass("dcp-high", podtemplate.DefaultRegistry(), log)(newDeployment(map[string]interface{}{}))
+		Expect(podSpecOf(result)).To(HaveKeyWithValue("priorityClassName", "dcp-high"))
+
+		result = SetPriorityClass("dcp-high", podtemplate.DefaultRegistry(), log)(newDeployment(map[string]interface{}{"priorityClassName": "custom"}))
+		Expect(podSpecOf(result)).To(HaveKeyWithValue("priorityClassName", "custom"))
+	})
+
+	It("should build post processors of the zone from configuration", func() {
+		cfg := &config.PostProcessingConfig{
+			ImageMirrors: []config.ImageMirrorConfig{
+				{Zone: "zone-b", Registry: "docker.io", Mirror: "mirror.zone-b"},
+				{Zone: "zone-a", Registry: "docker.io", Mirror: "mirror.zone-a"},
+			},
+			PriorityClassName: "dcp-high",
+		}
+		postProcessors := NewPostProcessors(cfg, "zone-a", podtemplate.DefaultRegistry(), log)
+		Expect(postProcessors).To(HaveLen(2))
+
+		obj := newDeployment(map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"name": "app", "image": "nginx"}},
+		})
+		for _, postProcessor := range postProcessors {
+			obj = postProcessor(obj)
+		}
+		podSpec := podSpecOf(obj)
+		Expect(podSpec["containers"]).To(Equal([]interface{}{
+			map[string]interface{}{"name": "app", "image": "mirror.zone-a/library/nginx"},
+		}))
+		Expect(podSpec).To(HaveKeyWithValue("priorityClassName", "dcp-high"))
+	})
+})
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	helmclient "github.com/mittwald/go-helm-client"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+const valuesSchemaUrl = "file:///values.schema.json"
+
+// ValuesIssue is a single violation of the chart values schema.
+type ValuesIssue struct {
+	// Field is the dotted path of the offending value, empty for the values document itself
+	Field string
+	Issue string
+}
+
+// ValuesValidationError is returned when values do not match the chart's values.schema.json.
+type ValuesValidationError struct {
+	Chart  string
+	Issues []ValuesIssue
+}
+
+func (e *ValuesValidationError) Error() string {
+	issues := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		if issue.Field == "" {
+			issues = append(issues, issue.Issue)
+		} else {
+			issues = append(issues, issue.Field+": "+issue.Issue)
+		}
+	}
+	return fmt.Sprintf("Values do not match schema of chart %s: %s", e.Chart, strings.Join(issues, "; "))
+}
+
+// validateValues checks the values of the chart spec against the schemas of the loaded chart
+// and its subcharts. It returns the values to template the chart with.
+func (h *HelmClientImpl) validateValues(helmChart *chart.Chart, chartSpec *helmclient.ChartSpec) (map[string]any, error) {
+	values, err := chartSpec.GetValuesMap(getter.All(h.client.GetSettings()))
+	if err != nil {
+		return nil, &ValuesValidationError{
+			Chart:  helmChart.Name(),
+			Issues: []ValuesIssue{{Issue: "Invalid values: " + err.Error()}},
+		}
+	}
+	coalesced, err := chartutil.CoalesceValues(helmChart, values)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to coalesce chart values")
+	}
+
+	issues, err := validateChartValues(helmChart, coalesced, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		return nil, &ValuesValidationError{Chart: helmChart.Name(), Issues: issues}
+	}
+	return values, nil
+}
+
+func validateChartValues(helmChart *chart.Chart, values map[string]any, prefix string) ([]ValuesIssue, error) {
+	issues := make([]ValuesIssue, 0)
+	if helmChart.Schema != nil {
+		schemaIssues, err := validateAgainstSchema(helmChart.Schema, values, prefix)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to validate values of chart %s", helmChart.Name())
+		}
+		issues = append(issues, schemaIssues...)
+	}
+
+	for _, subchart := range helmChart.Dependencies() {
+		subchartValues, ok := values[subchart.Name()].(map[string]any)
+		if !ok {
+			continue
+		}
+		subchartIssues, err := validateChartValues(subchart, subchartValues, prefix+subchart.Name()+".")
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, subchartIssues...)
+	}
+	return issues, nil
+}
+
+func validateAgainstSchema(schemaJSON []byte, values map[string]any, prefix string) ([]ValuesIssue, error) {
+	schema, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return nil, err
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(valuesSchemaUrl, schema); err != nil {
+		return nil, err
+	}
+	validator, err := compiler.Compile(valuesSchemaUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validator.Validate(values)
+	if err == nil {
+		return nil, nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, err
+	}
+
+	issues := make([]ValuesIssue, 0)
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		issues = append(issues, ValuesIssue{
+			Field: strings.TrimSuffix(prefix+fieldPath(unit.InstanceLocation), "."),
+			Issue: unit.Error.String(),
+		})
+	}
+	return issues, nil
+}
+
+// fieldPath converts a JSON pointer into a dotted values path
+func fieldPath(pointer string) string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		token = strings.ReplaceAll(token, "~1", "/")
+		tokens[i] = strings.ReplaceAll(token, "~0", "~")
+	}
+	return strings.Trim(strings.Join(tokens, "."), ".")
+}
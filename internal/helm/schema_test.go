@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+var _ = Describe("HelmClient values schema", func() {
+	const schema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "replicaCount": {"type": "integer", "minimum": 1},
+    "service": {
+      "type": "object",
+      "properties": {
+        "port": {"type": "integer"}
+      }
+    }
+  }
+}`
+
+	var (
+		client  *HelmClientImpl
+		repoDir string
+	)
+
+	templateArgs := func(values string) *TemplateArgs {
+		return &TemplateArgs{
+			ReleaseName: "test-release",
+			RepoUrl:     "file://" + repoDir,
+			ChartName:   "mirrored",
+			Namespace:   "default",
+			Version:     "1.0.0",
+			ValuesYaml:  values,
+		}
+	}
+
+	BeforeEach(func() {
+		repoDir = GinkgoT().TempDir()
+		chartDir, err := chartutil.Create("mirrored", GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		chart, err := loader.Load(chartDir)
+		Expect(err).NotTo(HaveOccurred())
+		chart.Metadata.Version = "1.0.0"
+		chart.Schema = []byte(schema)
+		_, err = chartutil.Save(chart, repoDir)
+		Expect(err).NotTo(HaveOccurred())
+		index, err := repo.IndexDirectory(repoDir, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.WriteFile(filepath.Join(repoDir, repositoryIndexFile), 0o600)).To(Succeed())
+
+		client = newMirrorClient("")
+	})
+
+	It("should template chart with valid values", func() {
+		_, err := client.Template(templateArgs("replicaCount: 2\n"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report field level issues", func() {
+		_, err := client.Template(templateArgs("replicaCount: 0\nservice:\n  port: http\n"))
+		Expect(err).To(HaveOccurred())
+
+		var valuesErr *ValuesValidationError
+		Expect(errors.As(err, &valuesErr)).To(BeTrue())
+		Expect(valuesErr.Chart).To(Equal("mirrored"))
+		fields := make([]string, 0)
+		for _, issue := range valuesErr.Issues {
+			fields = append(fields, issue.Field)
+			Expect(issue.Issue).NotTo(BeEmpty())
+		}
+		Expect(fields).To(ConsistOf("replicaCount", "service.port"))
+		Expect(err.Error()).To(ContainSubstring("service.port: "))
+	})
+
+	It("should report unparseable values", func() {
+		_, err := client.Template(templateArgs("replicaCount: [\n"))
+
+		var valuesErr *ValuesValidationError
+		Expect(errors.As(err, &valuesErr)).To(BeTrue())
+		Expect(valuesErr.Issues).To(HaveLen(1))
+		Expect(valuesErr.Issues[0].Field).To(BeEmpty())
+	})
+})
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/provenance"
+)
+
+var _ = Describe("HelmClient verification", func() {
+	var (
+		client  *HelmClientImpl
+		repoDir string
+		keyring *Keyring
+	)
+
+	// the keyrings of the signer and other keys are fixtures in testdata
+	loadKeyring := func(name string) *Keyring {
+		public, err := os.ReadFile(filepath.Join("testdata", name+".pub"))
+		Expect(err).NotTo(HaveOccurred())
+		return &Keyring{Source: "default/" + name, Data: public}
+	}
+
+	sign := func(archive string) {
+		signatory, err := provenance.NewFromKeyring(filepath.Join("testdata", "signer.secret"), "signer")
+		Expect(err).NotTo(HaveOccurred())
+		signature, err := signatory.ClearSign(archive)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(archive+".prov", []byte(signature), 0o600)).To(Succeed())
+	}
+
+	templateArgs := func(version string, keyring *Keyring) *TemplateArgs {
+		return &TemplateArgs{
+			ReleaseName: "test-release",
+			RepoUrl:     "file://" + repoDir,
+			ChartName:   "mirrored",
+			Namespace:   "default",
+			Version:     version,
+			Keyring:     keyring,
+		}
+	}
+
+	BeforeEach(func() {
+		keyring = loadKeyring("signer")
+
+		repoDir = filepath.Join(GinkgoT().TempDir(), "signed")
+		seedRepository(repoDir, "1.0.0", "1.1.0")
+		sign(filepath.Join(repoDir, "mirrored-1.1.0.tgz"))
+
+		client = newMirrorClient("")
+	})
+
+	It("should template signed chart", func() {
+		manifest, err := client.Template(templateArgs("1.1.0", keyring))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest).To(ContainSubstring("helm.sh/chart: mirrored-1.1.0"))
+	})
+
+	It("should refuse unsigned chart", func() {
+		_, err := client.Template(templateArgs("1.0.0", keyring))
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, ErrChartNotVerified)).To(BeTrue())
+	})
+
+	It("should refuse chart signed by unknown key", func() {
+		_, err := client.Template(templateArgs("1.1.0", loadKeyring("other")))
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, ErrChartNotVerified)).To(BeTrue())
+	})
+
+	It("should template unsigned chart without keyring", func() {
+		_, err := client.Template(templateArgs("1.0.0", nil))
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
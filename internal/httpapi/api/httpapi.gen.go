@@ -9,8 +9,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/oapi-codegen/runtime"
+	v1 "hiro.io/anyapplication/api/v1"
 )
 
 // ApplicationReport defines model for ApplicationReport.
@@ -63,6 +65,17 @@ type PVCResources struct {
 	StorageClass string            `json:"storage-class"`
 }
 
+// PeerStatus defines model for PeerStatus.
+type PeerStatus struct {
+	ConsecutiveFailures int32      `json:"consecutiveFailures"`
+	Error               *string    `json:"error,omitempty"`
+	LastSeen            *time.Time `json:"lastSeen,omitempty"`
+	LatencyMs           *int64     `json:"latencyMs,omitempty"`
+	Reachable           bool       `json:"reachable"`
+	Url                 string     `json:"url"`
+	Zone                string     `json:"zone"`
+}
+
 // PodEvent defines model for PodEvent.
 type PodEvent struct {
 	Message   string `json:"message"`
@@ -105,6 +118,18 @@ type WorkloadStatus struct {
 	Unavailable int32  `json:"unavailable"`
 }
 
+// ZoneHealth defines model for ZoneHealth.
+type ZoneHealth struct {
+	// Applications Number of applications placed in the zone
+	Applications *int32             `json:"applications,omitempty"`
+	Labels       *map[string]string `json:"labels,omitempty"`
+	Status       string             `json:"status"`
+	Zone         string             `json:"zone"`
+}
+
+// ZoneStatus defines model for ZoneStatus.
+type ZoneStatus = v1.ZoneStatus
+
 // AsPodResources returns the union data inside the ApplicationSpec_Resources_Item as a PodResources
 func (t ApplicationSpec_Resources_Item) AsPodResources() (PodResources, error) {
 	var body PodResources
@@ -175,6 +200,15 @@ type ServerInterface interface {
 	// Get Application Status
 	// (GET /applications/{namespace}/{name}/status)
 	GetApplicationStatus(w http.ResponseWriter, r *http.Request, namespace string, name string)
+	// Get Zone Status of the Application in this zone
+	// (GET /applications/{namespace}/{name}/zone)
+	GetZoneStatus(w http.ResponseWriter, r *http.Request, namespace string, name string)
+	// Health of the zone controller, used by peers to ping the zone
+	// (GET /health)
+	GetHealth(w http.ResponseWriter, r *http.Request)
+	// Reachability of the peer zones
+	// (GET /peers)
+	GetPeers(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -254,6 +288,68 @@ func (siw *ServerInterfaceWrapper) GetApplicationStatus(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
+// GetZoneStatus operation middleware
+func (siw *ServerInterfaceWrapper) GetZoneStatus(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "namespace" -------------
+	var namespace string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "namespace", r.PathValue("namespace"), &namespace, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "namespace", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", r.PathValue("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetZoneStatus(w, r, namespace, name)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHealth operation middleware
+func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHealth(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPeers operation middleware
+func (siw *ServerInterfaceWrapper) GetPeers(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPeers(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -376,6 +472,9 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 
 	m.HandleFunc("GET "+options.BaseURL+"/applications/{namespace}/{name}/specification", wrapper.GetApplicationSpec)
 	m.HandleFunc("GET "+options.BaseURL+"/applications/{namespace}/{name}/status", wrapper.GetApplicationStatus)
+	m.HandleFunc("GET "+options.BaseURL+"/applications/{namespace}/{name}/zone", wrapper.GetZoneStatus)
+	m.HandleFunc("GET "+options.BaseURL+"/health", wrapper.GetHealth)
+	m.HandleFunc("GET "+options.BaseURL+"/peers", wrapper.GetPeers)
 
 	return m
 }
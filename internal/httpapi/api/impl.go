@@ -5,8 +5,10 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/cockroachdb/errors"
 	v1 "hiro.io/anyapplication/api/v1"
 	ctrltypes "hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/helm"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -21,20 +23,26 @@ type ServerImpl struct {
 	applicationReports ApplicationReports
 	applications       ctrltypes.Applications
 	applicationSpecs   ApplicationSpecs
+	peers              PeerInventory
 	kubeClient         client.Client
+	zoneId             string
 }
 
 func NewServer(
 	applicationReports ApplicationReports,
 	applicationSpecs ApplicationSpecs,
 	applications ctrltypes.Applications,
+	peers PeerInventory,
 	kubeClient client.Client,
+	zoneId string,
 ) ServerInterface {
 	return ServerImpl{
 		applicationReports: applicationReports,
 		applicationSpecs:   applicationSpecs,
 		applications:       applications,
+		peers:              peers,
 		kubeClient:         kubeClient,
+		zoneId:             zoneId,
 	}
 }
 
@@ -66,6 +74,11 @@ func (s ServerImpl) GetApplicationSpec(w http.ResponseWriter, r *http.Request, n
 	}
 
 	applicationSpec, err := s.applicationSpecs.GetApplicationSpec(r.Context(), application)
+	var valuesErr *helm.ValuesValidationError
+	if errors.As(err, &valuesErr) {
+		s.replyValidationError(w, valuesErr)
+		return
+	}
 	if err != nil {
 		s.replyError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
 		return
@@ -77,6 +90,48 @@ func (s ServerImpl) GetApplicationSpec(w http.ResponseWriter, r *http.Request, n
 
 }
 
+func (s ServerImpl) GetZoneStatus(w http.ResponseWriter, r *http.Request, namespace string, name string) {
+	application := &v1.AnyApplication{}
+	if err := s.kubeClient.Get(r.Context(), client.ObjectKey{Namespace: namespace, Name: name}, application); err != nil {
+		s.replyError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	zoneStatus, found := application.Status.GetStatusFor(s.zoneId)
+	if !found {
+		s.replyError(w, http.StatusNotFound, "NOT_FOUND", "Application has no status in zone "+s.zoneId)
+		return
+	}
+	s.reply(w, zoneStatus)
+}
+
+func (s ServerImpl) GetHealth(w http.ResponseWriter, r *http.Request) {
+	if s.peers == nil {
+		s.reply(w, ZoneHealth{Zone: s.zoneId, Status: "ok"})
+		return
+	}
+	health, err := s.peers.LocalHealth(r.Context())
+	if err != nil {
+		s.replyError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+		return
+	}
+	s.reply(w, health)
+}
+
+func (s ServerImpl) GetPeers(w http.ResponseWriter, r *http.Request) {
+	peers := make([]PeerStatus, 0)
+	if s.peers != nil {
+		peers = s.peers.GetPeers()
+	}
+	s.reply(w, peers)
+}
+
+func (s ServerImpl) reply(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("failed to encode: %s", err)
+	}
+}
+
 func (s ServerImpl) replyError(w http.ResponseWriter, status int, code string, msg string) {
 	response := ErrorResponse{
 		Status:  status,
@@ -89,3 +144,30 @@ func (s ServerImpl) replyError(w http.ResponseWriter, status int, code string, m
 		log.Printf("failed to encode: %s", err)
 	}
 }
+
+func (s ServerImpl) replyValidationError(w http.ResponseWriter, valuesErr *helm.ValuesValidationError) {
+	details := make([]struct {
+		Field *string `json:"field,omitempty"`
+		Issue *string `json:"issue,omitempty"`
+	}, 0, len(valuesErr.Issues))
+	for _, issue := range valuesErr.Issues {
+		details = append(details, struct {
+			Field *string `json:"field,omitempty"`
+			Issue *string `json:"issue,omitempty"`
+		}{
+			Field: &issue.Field,
+			Issue: &issue.Issue,
+		})
+	}
+	response := ErrorResponse{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    "VALIDATION_ERROR",
+		Message: valuesErr.Error(),
+		Details: &details,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("failed to encode: %s", err)
+	}
+}
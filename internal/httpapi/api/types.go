@@ -16,3 +16,8 @@ type ApplicationReports interface {
 type ApplicationSpecs interface {
 	GetApplicationSpec(ctx context.Context, application *v1.AnyApplication) (*ApplicationSpec, error)
 }
+
+type PeerInventory interface {
+	GetPeers() []PeerStatus
+	LocalHealth(ctx context.Context) (*ZoneHealth, error)
+}
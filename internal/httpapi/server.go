@@ -14,6 +14,7 @@ import (
 
 type ApplicationApiOptions struct {
 	Address string
+	ZoneId  string
 }
 
 type ApiServer struct {
@@ -27,9 +28,10 @@ func NewHttpServer(
 	applicationReports api.ApplicationReports,
 	applicationSpecs api.ApplicationSpecs,
 	applications *ctrltypes.Applications,
+	peers api.PeerInventory,
 	kubeClient client.Client,
 ) *ApiServer {
-	serverImpl := api.NewServer(applicationReports, applicationSpecs, *applications, kubeClient)
+	serverImpl := api.NewServer(applicationReports, applicationSpecs, *applications, peers, kubeClient, options.ZoneId)
 	r := http.NewServeMux()
 	// get an `http.Handler` that we can use
 	httpHandler := api.HandlerFromMux(serverImpl, r)
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	"hiro.io/anyapplication/internal/config"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+const (
+	SinkTypeWebhook     = "webhook"
+	SinkTypeCloudEvents = "cloudevents"
+	SinkTypeSlack       = "slack"
+)
+
+// Notification describes a global state change or a failure of an application
+type Notification struct {
+	Application string    `json:"application"`
+	Namespace   string    `json:"namespace"`
+	Zone        string    `json:"zone"`
+	GlobalState string    `json:"globalState"`
+	Warning     bool      `json:"warning"`
+	Reason      string    `json:"reason"`
+	Message     string    `json:"message"`
+	Time        time.Time `json:"time"`
+}
+
+// Sink delivers notifications to an external system
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, notification *Notification) error
+}
+
+type sinkEntry struct {
+	sink         Sink
+	warningsOnly bool
+}
+
+// Notifier sends notifications to all configured sinks. Notifications are sent in the
+// background so that status updates do not wait for the sinks.
+type Notifier struct {
+	sinks   []sinkEntry
+	timeout time.Duration
+	log     logr.Logger
+}
+
+func NewNotifier(notifications *config.NotificationsConfig, log logr.Logger) (*Notifier, error) {
+	timeout := notifications.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	httpClient := &http.Client{Timeout: timeout}
+
+	sinks := make([]sinkEntry, 0, len(notifications.Sinks))
+	for _, sinkConfig := range notifications.Sinks {
+		sink, err := newSink(&sinkConfig, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sinkEntry{sink: sink, warningsOnly: sinkConfig.WarningsOnly})
+	}
+	return &Notifier{
+		sinks:   sinks,
+		timeout: timeout,
+		log:     log.WithName("Notifier"),
+	}, nil
+}
+
+func newSink(sinkConfig *config.NotificationSinkConfig, httpClient *http.Client) (Sink, error) {
+	if sinkConfig.Url == "" {
+		return nil, errors.Newf("Notification sink %s has no url", sinkConfig.Name)
+	}
+	poster := &poster{url: sinkConfig.Url, headers: sinkConfig.Headers, httpClient: httpClient}
+	switch sinkConfig.Type {
+	case SinkTypeWebhook:
+		return &WebhookSink{name: sinkConfig.Name, poster: poster}, nil
+	case SinkTypeCloudEvents:
+		return &CloudEventsSink{name: sinkConfig.Name, poster: poster}, nil
+	case SinkTypeSlack:
+		return &SlackSink{name: sinkConfig.Name, poster: poster}, nil
+	default:
+		return nil, errors.Newf("Unknown type %s of notification sink %s", sinkConfig.Type, sinkConfig.Name)
+	}
+}
+
+// Notify sends the notification to the sinks in the background
+func (n *Notifier) Notify(notification *Notification) {
+	for _, entry := range n.sinks {
+		if entry.warningsOnly && !notification.Warning {
+			continue
+		}
+		go n.send(entry.sink, notification)
+	}
+}
+
+func (n *Notifier) send(sink Sink, notification *Notification) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+	if err := sink.Send(ctx, notification); err != nil {
+		n.log.Error(err, "Failed to send notification", "sink", sink.Name(),
+			"application", notification.Namespace+"/"+notification.Application, "reason", notification.Reason)
+	}
+}
+
+// poster posts JSON documents to the url of a sink
+type poster struct {
+	url        string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+func (p *poster) post(ctx context.Context, contentType string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal notification")
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create request to %s", p.url)
+	}
+	request.Header.Set("Content-Type", contentType)
+	for name, value := range p.headers {
+		request.Header.Set(name, value)
+	}
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to post to %s", p.url)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return errors.Newf("Post to %s failed with status %d", p.url, response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"hiro.io/anyapplication/internal/config"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type receivedRequest struct {
+	path        string
+	contentType string
+	headers     http.Header
+	body        []byte
+}
+
+// receiver records the requests of the sinks
+type receiver struct {
+	mu       sync.Mutex
+	server   *httptest.Server
+	requests []receivedRequest
+	status   int
+}
+
+func newReceiver() *receiver {
+	r := &receiver{status: http.StatusOK}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, receivedRequest{
+			path:        request.URL.Path,
+			contentType: request.Header.Get("Content-Type"),
+			headers:     request.Header.Clone(),
+			body:        body,
+		})
+		w.WriteHeader(r.status)
+	}))
+	return r
+}
+
+func (r *receiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest{}, r.requests...)
+}
+
+var _ = Describe("Notifier", func() {
+	var (
+		rcv          *receiver
+		notification *Notification
+	)
+
+	BeforeEach(func() {
+		rcv = newReceiver()
+		DeferCleanup(rcv.server.Close)
+		notification = &Notification{
+			Application: "test-app",
+			Namespace:   "default",
+			Zone:        "zone-a",
+			GlobalState: "Failure",
+			Warning:     true,
+			Reason:      "GlobalFailure",
+			Message:     "Global state changed to 'Failure'. ",
+			Time:        time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		}
+	})
+
+	newNotifier := func(sinks ...config.NotificationSinkConfig) *Notifier {
+		notifier, err := NewNotifier(&config.NotificationsConfig{Timeout: time.Second, Sinks: sinks}, logf.Log)
+		Expect(err).NotTo(HaveOccurred())
+		return notifier
+	}
+
+	It("should post the notification to a webhook", func() {
+		notifier := newNotifier(config.NotificationSinkConfig{
+			Name:    "webhook",
+			Type:    SinkTypeWebhook,
+			Url:     rcv.server.URL + "/hook",
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		})
+		notifier.Notify(notification)
+
+		Eventually(rcv.received).Should(HaveLen(1))
+		request := rcv.received()[0]
+		Expect(request.path).To(Equal("/hook"))
+		Expect(request.contentType).To(Equal("application/json"))
+		Expect(request.headers.Get("Authorization")).To(Equal("Bearer token"))
+
+		received := &Notification{}
+		Expect(json.Unmarshal(request.body, received)).To(Succeed())
+		Expect(received).To(Equal(notification))
+	})
+
+	It("should post the notification as structured CloudEvent", func() {
+		notifier := newNotifier(config.NotificationSinkConfig{Name: "events", Type: SinkTypeCloudEvents, Url: rcv.server.URL})
+		notifier.Notify(notification)
+
+		Eventually(rcv.received).Should(HaveLen(1))
+		request := rcv.received()[0]
+		Expect(request.contentType).To(Equal(CloudEventsContentType))
+
+		event := &CloudEvent{}
+		Expect(json.Unmarshal(request.body, event)).To(Succeed())
+		Expect(event.SpecVersion).To(Equal("1.0"))
+		Expect(event.Id).NotTo(BeEmpty())
+		Expect(event.Source).To(Equal("/zones/zone-a"))
+		Expect(event.Type).To(Equal(CloudEventTypeFailure))
+		Expect(event.Subject).To(Equal("default/test-app"))
+		Expect(event.Time).To(Equal("2025-01-01T12:00:00Z"))
+		Expect(event.DataContentType).To(Equal("application/json"))
+		Expect(event.Data).To(Equal(notification))
+	})
+
+	It("should post a text message to a Slack webhook", func() {
+		notifier := newNotifier(config.NotificationSinkConfig{Name: "slack", Type: SinkTypeSlack, Url: rcv.server.URL})
+		notifier.Notify(notification)
+
+		Eventually(rcv.received).Should(HaveLen(1))
+		message := &SlackMessage{}
+		Expect(json.Unmarshal(rcv.received()[0].body, message)).To(Succeed())
+		Expect(message.Text).To(Equal(
+			":warning: *default/test-app* in zone zone-a: Global state changed to 'Failure'. (GlobalFailure). Global state: Failure",
+		))
+	})
+
+	It("should send state changes only to sinks which are not limited to warnings", func() {
+		notifier := newNotifier(
+			config.NotificationSinkConfig{Name: "all", Type: SinkTypeWebhook, Url: rcv.server.URL + "/all"},
+			config.NotificationSinkConfig{Name: "warnings", Type: SinkTypeWebhook, Url: rcv.server.URL + "/warnings", WarningsOnly: true},
+		)
+		notification.Warning = false
+		notifier.Notify(notification)
+
+		Eventually(rcv.received).Should(HaveLen(1))
+		Consistently(rcv.received, 200*time.Millisecond).Should(HaveLen(1))
+		Expect(rcv.received()[0].path).To(Equal("/all"))
+	})
+
+	It("should fail when the receiver rejects the notification", func() {
+		rcv.status = http.StatusInternalServerError
+		sink := &WebhookSink{name: "webhook", poster: &poster{url: rcv.server.URL, httpClient: http.DefaultClient}}
+
+		err := sink.Send(context.Background(), notification)
+		Expect(err).To(MatchError(ContainSubstring("failed with status 500")))
+	})
+
+	It("should reject sinks of unknown type or without url", func() {
+		_, err := NewNotifier(&config.NotificationsConfig{Sinks: []config.NotificationSinkConfig{
+			{Name: "mail", Type: "smtp", Url: "smtp://localhost"},
+		}}, logf.Log)
+		Expect(err).To(MatchError("Unknown type smtp of notification sink mail"))
+
+		_, err = NewNotifier(&config.NotificationsConfig{Sinks: []config.NotificationSinkConfig{
+			{Name: "webhook", Type: SinkTypeWebhook},
+		}}, logf.Log)
+		Expect(err).To(MatchError("Notification sink webhook has no url"))
+	})
+})
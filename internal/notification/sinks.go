@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+const (
+	CloudEventsContentType = "application/cloudevents+json"
+	CloudEventsSpecVersion = "1.0"
+	// CloudEventTypeStateChange is the type of CloudEvents about global state changes
+	CloudEventTypeStateChange = "io.hiro.dcp.anyapplication.statechange"
+	// CloudEventTypeFailure is the type of CloudEvents about failures
+	CloudEventTypeFailure = "io.hiro.dcp.anyapplication.failure"
+)
+
+// WebhookSink posts the notification as JSON document
+type WebhookSink struct {
+	name   string
+	poster *poster
+}
+
+func (s *WebhookSink) Name() string {
+	return s.name
+}
+
+func (s *WebhookSink) Send(ctx context.Context, notification *Notification) error {
+	return s.poster.post(ctx, "application/json", notification)
+}
+
+// CloudEvent is a CloudEvent in the structured content mode of the HTTP binding
+type CloudEvent struct {
+	SpecVersion     string        `json:"specversion"`
+	Id              string        `json:"id"`
+	Source          string        `json:"source"`
+	Type            string        `json:"type"`
+	Subject         string        `json:"subject"`
+	Time            string        `json:"time"`
+	DataContentType string        `json:"datacontenttype"`
+	Data            *Notification `json:"data"`
+}
+
+// CloudEventsSink posts the notification as CloudEvent, the zone is the source of the event
+type CloudEventsSink struct {
+	name   string
+	poster *poster
+}
+
+func (s *CloudEventsSink) Name() string {
+	return s.name
+}
+
+func (s *CloudEventsSink) Send(ctx context.Context, notification *Notification) error {
+	eventType := CloudEventTypeStateChange
+	if notification.Warning {
+		eventType = CloudEventTypeFailure
+	}
+	event := CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		Id:              string(uuid.NewUUID()),
+		Source:          "/zones/" + notification.Zone,
+		Type:            eventType,
+		Subject:         notification.Namespace + "/" + notification.Application,
+		Time:            notification.Time.UTC().Format(time.RFC3339),
+		DataContentType: "application/json",
+		Data:            notification,
+	}
+	return s.poster.post(ctx, CloudEventsContentType, &event)
+}
+
+// SlackMessage is the payload of Slack incoming webhooks
+type SlackMessage struct {
+	Text string `json:"text"`
+}
+
+// SlackSink posts the notification as text message to a Slack compatible incoming webhook
+type SlackSink struct {
+	name   string
+	poster *poster
+}
+
+func (s *SlackSink) Name() string {
+	return s.name
+}
+
+func (s *SlackSink) Send(ctx context.Context, notification *Notification) error {
+	icon := ":information_source:"
+	if notification.Warning {
+		icon = ":warning:"
+	}
+	text := fmt.Sprintf("%s *%s/%s* in zone %s: %s (%s). Global state: %s",
+		icon, notification.Namespace, notification.Application, notification.Zone,
+		strings.TrimSpace(notification.Message), notification.Reason, notification.GlobalState)
+	return s.poster.post(ctx, "application/json", &SlackMessage{Text: text})
+}
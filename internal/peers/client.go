@@ -0,0 +1,379 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package peers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	"github.com/samber/mo"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
+	"hiro.io/anyapplication/internal/config"
+	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/httpapi/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultPingInterval     = 10 * time.Second
+	DefaultTimeout          = 2 * time.Second
+	DefaultFailureThreshold = 3
+)
+
+// ErrNotFound is returned when the peer does not know the requested resource,
+// e.g. the application has no status in the zone of the peer
+var ErrNotFound = errors.New("Not found")
+
+type peer struct {
+	url  string
+	zone string
+	// reachable is unknown until the first ping of the peer completes
+	reachable           mo.Option[bool]
+	consecutiveFailures int
+	lastSeen            time.Time
+	latency             time.Duration
+	lastError           string
+	labels              map[string]string
+	applications        int
+}
+
+// PeerClient talks to the controllers of other zones over their HTTP API.
+// Peers are pinged periodically, a peer becomes unreachable after the configured
+// number of consecutive failed pings and reachable again with the first successful one.
+// A peer which never responded is unreachable once its first ping fails.
+type PeerClient struct {
+	mu               sync.RWMutex
+	peers            []*peer
+	zoneId           string
+	zoneLabels       map[string]string
+	localApps        int
+	kubeClient       client.Client
+	httpClient       *http.Client
+	pingInterval     time.Duration
+	failureThreshold int
+	clock            clock.Clock
+	log              logr.Logger
+}
+
+func NewPeerClient(
+	peers []config.PeerConfig,
+	peering *config.PeeringConfig,
+	zoneId string,
+	zoneLabels map[string]string,
+	kubeClient client.Client,
+	clock clock.Clock,
+	log logr.Logger,
+) *PeerClient {
+	pingInterval := peering.PingInterval
+	if pingInterval <= 0 {
+		pingInterval = DefaultPingInterval
+	}
+	timeout := peering.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	failureThreshold := peering.FailureThreshold
+	if failureThreshold <= 0 {
+		failureThreshold = DefaultFailureThreshold
+	}
+	clientPeers := make([]*peer, 0, len(peers))
+	for _, p := range peers {
+		clientPeers = append(clientPeers, &peer{url: normalizeUrl(p.Url), zone: p.Zone})
+	}
+	return &PeerClient{
+		peers:            clientPeers,
+		zoneId:           zoneId,
+		zoneLabels:       zoneLabels,
+		kubeClient:       kubeClient,
+		httpClient:       &http.Client{Timeout: timeout},
+		pingInterval:     pingInterval,
+		failureThreshold: failureThreshold,
+		clock:            clock,
+		log:              log.WithName("PeerClient"),
+	}
+}
+
+// Run pings all peers until the context is done
+func (c *PeerClient) Run(ctx context.Context) {
+	c.PingAll(ctx)
+
+	ticker := time.NewTicker(c.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.PingAll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// PingAll pings all peers concurrently and updates their reachability and the load of the own zone
+func (c *PeerClient) PingAll(ctx context.Context) {
+	if health, err := c.LocalHealth(ctx); err != nil {
+		c.log.Error(err, "Failed to determine own zone health")
+	} else {
+		c.mu.Lock()
+		c.localApps = int(*health.Applications)
+		c.mu.Unlock()
+	}
+
+	c.mu.RLock()
+	urls := make([]string, 0, len(c.peers))
+	for _, p := range c.peers {
+		urls = append(urls, p.url)
+	}
+	c.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, peerUrl := range urls {
+		wg.Add(1)
+		go func(peerUrl string) {
+			defer wg.Done()
+			start := time.Now()
+			health, err := c.Ping(ctx, peerUrl)
+			c.recordPing(peerUrl, health, time.Since(start), err)
+		}(peerUrl)
+	}
+	wg.Wait()
+}
+
+// Ping requests the health of a peer
+func (c *PeerClient) Ping(ctx context.Context, peerUrl string) (*api.ZoneHealth, error) {
+	health := &api.ZoneHealth{}
+	if err := c.get(ctx, peerUrl+"/health", health); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// LocalHealth reports the health of the own zone with the number of applications placed in it
+func (c *PeerClient) LocalHealth(ctx context.Context) (*api.ZoneHealth, error) {
+	applications := &v1.AnyApplicationList{}
+	if err := c.kubeClient.List(ctx, applications); err != nil {
+		return nil, errors.Wrap(err, "Failed to list applications")
+	}
+	placed := int32(0)
+	for _, application := range applications.Items {
+		for _, placement := range application.Status.Ownership.Placements {
+			if placement.Zone == c.zoneId {
+				placed++
+				break
+			}
+		}
+	}
+	health := &api.ZoneHealth{
+		Zone:         c.zoneId,
+		Status:       "ok",
+		Applications: &placed,
+	}
+	if len(c.zoneLabels) > 0 {
+		labels := c.zoneLabels
+		health.Labels = &labels
+	}
+	return health, nil
+}
+
+// GetZoneStatus fetches the status the peer of the zone reports for the application
+func (c *PeerClient) GetZoneStatus(ctx context.Context, zoneId string, namespace string, name string) (*v1.ZoneStatus, error) {
+	peerUrl, found := c.urlOf(zoneId)
+	if !found {
+		return nil, errors.Newf("Zone %s is not a known peer", zoneId)
+	}
+	statusUrl := fmt.Sprintf("%s/applications/%s/%s/zone", peerUrl, url.PathEscape(namespace), url.PathEscape(name))
+	zoneStatus := &api.ZoneStatus{}
+	if err := c.get(ctx, statusUrl, zoneStatus); err != nil {
+		return nil, err
+	}
+	return zoneStatus, nil
+}
+
+// GetPeers returns the reachability of all peers
+func (c *PeerClient) GetPeers() []api.PeerStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	statuses := make([]api.PeerStatus, 0, len(c.peers))
+	for _, p := range c.peers {
+		status := api.PeerStatus{
+			Url:                 p.url,
+			Zone:                p.zone,
+			Reachable:           p.reachable.OrElse(false),
+			ConsecutiveFailures: int32(p.consecutiveFailures),
+		}
+		if !p.lastSeen.IsZero() {
+			lastSeen := p.lastSeen
+			latencyMs := p.latency.Milliseconds()
+			status.LastSeen = &lastSeen
+			status.LatencyMs = &latencyMs
+		}
+		if p.lastError != "" {
+			lastError := p.lastError
+			status.Error = &lastError
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// ReachableZones returns the zones of the reachable peers
+func (c *PeerClient) ReachableZones() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	zones := make([]string, 0, len(c.peers))
+	for _, p := range c.peers {
+		if p.reachable.OrElse(false) && p.zone != "" {
+			zones = append(zones, p.zone)
+		}
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// Zones describes the own zone followed by the reachable peer zones
+func (c *PeerClient) Zones() []types.ZoneInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	zones := []types.ZoneInfo{{ZoneId: c.zoneId, Labels: c.zoneLabels, Applications: c.localApps}}
+	for _, p := range c.peers {
+		if p.reachable.OrElse(false) && p.zone != "" && p.zone != c.zoneId {
+			zones = append(zones, types.ZoneInfo{ZoneId: p.zone, Labels: p.labels, Applications: p.applications})
+		}
+	}
+	sort.Slice(zones[1:], func(i, j int) bool {
+		return zones[i+1].ZoneId < zones[j+1].ZoneId
+	})
+	return zones
+}
+
+// IsUnreachable returns true when the zone is a peer which is not reachable after its pings.
+// The own zone, zones which are not peers and peers which were not pinged yet are not unreachable.
+func (c *PeerClient) IsUnreachable(zoneId string) bool {
+	if zoneId == c.zoneId {
+		return false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, p := range c.peers {
+		if p.zone == zoneId {
+			reachable, pinged := p.reachable.Get()
+			return pinged && !reachable
+		}
+	}
+	return false
+}
+
+func (c *PeerClient) recordPing(peerUrl string, health *api.ZoneHealth, latency time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, p := range c.peers {
+		if p.url != peerUrl {
+			continue
+		}
+		pingDuration.WithLabelValues(p.url).Observe(latency.Seconds())
+		if err != nil {
+			p.consecutiveFailures++
+			p.lastError = err.Error()
+			pingFailures.WithLabelValues(p.url).Inc()
+			wasReachable := p.reachable.OrElse(false)
+			reachable := wasReachable && p.consecutiveFailures < c.failureThreshold
+			if wasReachable && !reachable {
+				c.log.Info("Peer became unreachable", "peer", p.url, "zone", p.zone, "error", err.Error())
+			}
+			p.reachable = mo.Some(reachable)
+		} else {
+			if !p.reachable.OrElse(false) {
+				c.log.Info("Peer became reachable", "peer", p.url, "zone", health.Zone)
+			}
+			if p.zone != "" && p.zone != health.Zone {
+				c.log.Info("Peer reports a different zone", "peer", p.url, "configured", p.zone, "reported", health.Zone)
+			}
+			p.zone = health.Zone
+			p.labels = nil
+			if health.Labels != nil {
+				p.labels = *health.Labels
+			}
+			p.applications = 0
+			if health.Applications != nil {
+				p.applications = int(*health.Applications)
+			}
+			p.reachable = mo.Some(true)
+			p.consecutiveFailures = 0
+			p.lastError = ""
+			p.lastSeen = c.clock.NowTime().Time
+			p.latency = latency
+		}
+		peerReachable.WithLabelValues(p.url).Set(boolToFloat(p.reachable.OrElse(false)))
+		return
+	}
+}
+
+func (c *PeerClient) urlOf(zoneId string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, p := range c.peers {
+		if p.zone == zoneId {
+			return p.url, true
+		}
+	}
+	return "", false
+}
+
+func (c *PeerClient) get(ctx context.Context, requestUrl string, result interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl, nil)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create request to %s", requestUrl)
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to request %s", requestUrl)
+	}
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			c.log.Error(err, "Failed to close response body", "url", requestUrl)
+		}
+	}()
+
+	if response.StatusCode == http.StatusNotFound {
+		return errors.Wrapf(ErrNotFound, "Request to %s", requestUrl)
+	}
+	if response.StatusCode != http.StatusOK {
+		return errors.Newf("Request to %s failed with status %d", requestUrl, response.StatusCode)
+	}
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return errors.Wrapf(err, "Failed to decode response of %s", requestUrl)
+	}
+	return nil
+}
+
+func normalizeUrl(peerUrl string) string {
+	peerUrl = strings.TrimSuffix(strings.TrimSpace(peerUrl), "/")
+	if !strings.Contains(peerUrl, "://") {
+		peerUrl = "http://" + peerUrl
+	}
+	return peerUrl
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
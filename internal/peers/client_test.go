@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package peers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "hiro.io/anyapplication/api/v1"
+	"hiro.io/anyapplication/internal/clock"
+	"hiro.io/anyapplication/internal/config"
+	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/httpapi/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakePeer struct {
+	server  *httptest.Server
+	zone    string
+	healthy atomic.Bool
+}
+
+func newFakePeer(zone string) *fakePeer {
+	peer := &fakePeer{zone: zone}
+	peer.healthy.Store(true)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		if !peer.healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		applications := int32(len(zone))
+		labels := map[string]string{v1.RegionLabel: "region-" + zone}
+		_ = json.NewEncoder(w).Encode(api.ZoneHealth{
+			Zone:         zone,
+			Status:       "ok",
+			Labels:       &labels,
+			Applications: &applications,
+		})
+	})
+	mux.HandleFunc("GET /applications/{namespace}/{name}/zone", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("name") != "app" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v1.ZoneStatus{ZoneId: zone, ZoneVersion: 7, ChartVersion: "1.0.0"})
+	})
+	peer.server = httptest.NewServer(mux)
+	return peer
+}
+
+var _ = Describe("PeerClient", func() {
+	var (
+		ctx       context.Context
+		fakeClock *clock.FakeClock
+		peerA     *fakePeer
+		peerB     *fakePeer
+		client    *PeerClient
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		fakeClock = clock.NewFakeClock()
+		fakeClock.SetNow(1000)
+		peerA = newFakePeer("zone-a")
+		peerB = newFakePeer("zone-b")
+		DeferCleanup(peerA.server.Close)
+		DeferCleanup(peerB.server.Close)
+
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+		placed := &v1.AnyApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "placed", Namespace: "default"},
+			Spec:       v1.AnyApplicationSpec{PlacementStrategy: v1.PlacementStrategySpec{Strategy: v1.PlacementStrategyGlobal}},
+			Status: v1.AnyApplicationStatus{Ownership: v1.OwnershipStatus{
+				Epoch: 1, Owner: "zone-a", State: v1.OperationalGlobalState,
+				Placements: []v1.Placement{{Zone: "zone-a"}, {Zone: "zone-local"}},
+			}},
+		}
+		elsewhere := placed.DeepCopy()
+		elsewhere.Name = "elsewhere"
+		elsewhere.Status.Ownership.Placements = []v1.Placement{{Zone: "zone-b"}}
+		kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(placed, elsewhere).Build()
+
+		client = NewPeerClient(
+			[]config.PeerConfig{
+				{Url: peerA.server.URL + "/"},
+				// scheme is optional
+				{Url: strings.TrimPrefix(peerB.server.URL, "http://"), Zone: "zone-b"},
+			},
+			&config.PeeringConfig{Timeout: time.Second, FailureThreshold: 2},
+			"zone-local",
+			map[string]string{v1.RegionLabel: "region-local"},
+			kubeClient,
+			fakeClock,
+			logr.Discard(),
+		)
+	})
+
+	It("should learn zones of reachable peers", func() {
+		Expect(client.ReachableZones()).To(BeEmpty())
+
+		client.PingAll(ctx)
+
+		Expect(client.ReachableZones()).To(Equal([]string{"zone-a", "zone-b"}))
+		statuses := client.GetPeers()
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].Url).To(Equal(peerA.server.URL))
+		Expect(statuses[0].Zone).To(Equal("zone-a"))
+		Expect(statuses[0].Reachable).To(BeTrue())
+		Expect(statuses[0].LastSeen).NotTo(BeNil())
+		Expect(*statuses[0].LastSeen).To(Equal(fakeClock.NowTime().Time))
+		Expect(statuses[0].Error).To(BeNil())
+	})
+
+	It("should describe the own zone and reachable peer zones", func() {
+		health, err := client.LocalHealth(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health.Zone).To(Equal("zone-local"))
+		Expect(*health.Applications).To(Equal(int32(1)))
+
+		peerA.healthy.Store(false)
+		client.PingAll(ctx)
+		client.PingAll(ctx)
+
+		Expect(client.Zones()).To(Equal([]types.ZoneInfo{
+			{ZoneId: "zone-local", Labels: map[string]string{v1.RegionLabel: "region-local"}, Applications: 1},
+			{ZoneId: "zone-b", Labels: map[string]string{v1.RegionLabel: "region-zone-b"}, Applications: 6},
+		}))
+	})
+
+	It("should mark peers unreachable after consecutive failures", func() {
+		client.PingAll(ctx)
+		peerB.healthy.Store(false)
+
+		client.PingAll(ctx)
+		Expect(client.IsUnreachable("zone-b")).To(BeFalse())
+		Expect(client.ReachableZones()).To(Equal([]string{"zone-a", "zone-b"}))
+
+		client.PingAll(ctx)
+		Expect(client.IsUnreachable("zone-b")).To(BeTrue())
+		Expect(client.ReachableZones()).To(Equal([]string{"zone-a"}))
+		status := client.GetPeers()[1]
+		Expect(status.Reachable).To(BeFalse())
+		Expect(status.ConsecutiveFailures).To(Equal(int32(2)))
+		Expect(*status.Error).To(ContainSubstring("failed with status 503"))
+
+		peerB.healthy.Store(true)
+		client.PingAll(ctx)
+		Expect(client.IsUnreachable("zone-b")).To(BeFalse())
+		Expect(client.GetPeers()[1].Error).To(BeNil())
+	})
+
+	It("should neither place on nor give up peers before their first ping", func() {
+		Expect(client.ReachableZones()).To(BeEmpty())
+		Expect(client.Zones()).To(HaveLen(1))
+		Expect(client.IsUnreachable("zone-b")).To(BeFalse())
+		Expect(client.GetPeers()[1].Reachable).To(BeFalse())
+
+		peerB.healthy.Store(false)
+		client.PingAll(ctx)
+		Expect(client.IsUnreachable("zone-b")).To(BeTrue())
+		Expect(client.ReachableZones()).To(Equal([]string{"zone-a"}))
+		Expect(client.IsUnreachable("zone-a")).To(BeFalse())
+	})
+
+	It("should never consider the own zone or unknown zones unreachable", func() {
+		peerA.server.Close()
+		client.PingAll(ctx)
+		client.PingAll(ctx)
+
+		Expect(client.IsUnreachable("zone-local")).To(BeFalse())
+		Expect(client.IsUnreachable("zone-unknown")).To(BeFalse())
+		// zone of peer a was never learned
+		Expect(client.IsUnreachable("zone-a")).To(BeFalse())
+		Expect(client.GetPeers()[0].ConsecutiveFailures).To(Equal(int32(2)))
+	})
+
+	It("should fetch remote zone status", func() {
+		client.PingAll(ctx)
+
+		zoneStatus, err := client.GetZoneStatus(ctx, "zone-a", "default", "app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zoneStatus).To(Equal(&v1.ZoneStatus{ZoneId: "zone-a", ZoneVersion: 7, ChartVersion: "1.0.0"}))
+
+		_, err = client.GetZoneStatus(ctx, "zone-a", "default", "missing")
+		Expect(errors.Is(err, ErrNotFound)).To(BeTrue())
+
+		_, err = client.GetZoneStatus(ctx, "zone-unknown", "default", "app")
+		Expect(err).To(MatchError(ContainSubstring("not a known peer")))
+	})
+})
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package peers
+
+import (
+	"sort"
+
+	mapset "github.com/deckarep/golang-set/v2"
+	"hiro.io/anyapplication/internal/controller/types"
+)
+
+type FakeReachability struct {
+	zones       map[string]types.ZoneInfo
+	unreachable mapset.Set[string]
+}
+
+func NewFakeReachability(unreachable ...string) *FakeReachability {
+	return &FakeReachability{zones: make(map[string]types.ZoneInfo), unreachable: mapset.NewSet(unreachable...)}
+}
+
+func (f *FakeReachability) IsUnreachable(zoneId string) bool {
+	return f.unreachable.Contains(zoneId)
+}
+
+func (f *FakeReachability) SetUnreachable(zoneId string, unreachable bool) {
+	if unreachable {
+		f.unreachable.Add(zoneId)
+	} else {
+		f.unreachable.Remove(zoneId)
+	}
+}
+
+func (f *FakeReachability) Zones() []types.ZoneInfo {
+	zones := make([]types.ZoneInfo, 0, len(f.zones))
+	for zoneId, zone := range f.zones {
+		if !f.unreachable.Contains(zoneId) {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].ZoneId < zones[j].ZoneId
+	})
+	return zones
+}
+
+// AddZones adds peer zones, they are reachable unless marked otherwise
+func (f *FakeReachability) AddZones(zones ...string) {
+	for _, zoneId := range zones {
+		f.zones[zoneId] = types.ZoneInfo{ZoneId: zoneId}
+	}
+}
+
+// SetZone adds or replaces the description of a zone
+func (f *FakeReachability) SetZone(zone types.ZoneInfo) {
+	f.zones[zone.ZoneId] = zone
+}
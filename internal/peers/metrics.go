@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package peers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	peerReachable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "anyapplication_peer_reachable",
+		Help: "Whether the peer zone responds to health pings",
+	}, []string{"peer"})
+	pingFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "anyapplication_peer_ping_failures_total",
+		Help: "Number of failed health pings of the peer zone",
+	}, []string{"peer"})
+	pingDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "anyapplication_peer_ping_duration_seconds",
+		Help:    "Duration of health pings of the peer zone",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"peer"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(peerReachable, pingFailures, pingDuration)
+}
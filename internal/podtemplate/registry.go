@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package podtemplate
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"hiro.io/anyapplication/internal/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const pathSeparator = "."
+
+var defaultPaths = map[schema.GroupKind][]string{
+	{Group: "", Kind: "Pod"}:                   {},
+	{Group: "", Kind: "ReplicationController"}: {"spec", "template"},
+	{Group: "apps", Kind: "Deployment"}:        {"spec", "template"},
+	{Group: "apps", Kind: "StatefulSet"}:       {"spec", "template"},
+	{Group: "apps", Kind: "DaemonSet"}:         {"spec", "template"},
+	{Group: "apps", Kind: "ReplicaSet"}:        {"spec", "template"},
+	{Group: "batch", Kind: "Job"}:              {"spec", "template"},
+	{Group: "batch", Kind: "CronJob"}:          {"spec", "jobTemplate", "spec", "template"},
+}
+
+// Registry knows where kinds keep the template of the pods they create.
+// A nil registry resolves the built-in kinds only.
+type Registry struct {
+	paths map[schema.GroupKind][]string
+}
+
+// DefaultRegistry resolves the built-in workload kinds
+func DefaultRegistry() *Registry {
+	registry, _ := NewRegistry(nil)
+	return registry
+}
+
+// NewRegistry extends the built-in kinds with the configured ones, configured paths take precedence
+func NewRegistry(podTemplates []config.PodTemplateConfig) (*Registry, error) {
+	paths := make(map[schema.GroupKind][]string, len(defaultPaths)+len(podTemplates))
+	for groupKind, path := range defaultPaths {
+		paths[groupKind] = path
+	}
+	for _, podTemplate := range podTemplates {
+		if podTemplate.Kind == "" {
+			return nil, errors.Newf("Pod template of group '%s' has no kind", podTemplate.Group)
+		}
+		path := splitPath(podTemplate.Path)
+		if len(path) == 0 {
+			return nil, errors.Newf("Pod template of kind '%s' has no path", podTemplate.Kind)
+		}
+		paths[schema.GroupKind{Group: podTemplate.Group, Kind: podTemplate.Kind}] = path
+	}
+	return &Registry{paths: paths}, nil
+}
+
+// TemplatePath returns the path of the pod template within the object.
+// The path of a Pod is empty as the Pod is its own template.
+func (r *Registry) TemplatePath(obj *unstructured.Unstructured) ([]string, bool) {
+	paths := defaultPaths
+	if r != nil {
+		paths = r.paths
+	}
+	gvk := obj.GroupVersionKind()
+	if path, found := paths[gvk.GroupKind()]; found {
+		return path, true
+	}
+	// objects without apiVersion are matched by kind
+	if gvk.Group == "" && gvk.Version == "" {
+		for groupKind, path := range paths {
+			if groupKind.Kind == gvk.Kind {
+				return path, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// PodSpecPath returns the path of the pod spec within the object
+func (r *Registry) PodSpecPath(obj *unstructured.Unstructured) ([]string, bool) {
+	path, found := r.TemplatePath(obj)
+	if !found {
+		return nil, false
+	}
+	return append(append(make([]string, 0, len(path)+1), path...), "spec"), true
+}
+
+// PodTemplate returns a copy of the pod template of the object
+func (r *Registry) PodTemplate(obj *unstructured.Unstructured) (map[string]interface{}, bool) {
+	path, found := r.TemplatePath(obj)
+	if !found {
+		return nil, false
+	}
+	template, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil || !found {
+		return nil, false
+	}
+	return template, true
+}
+
+// IsWorkload returns true for objects creating pods
+func (r *Registry) IsWorkload(obj *unstructured.Unstructured) bool {
+	_, found := r.TemplatePath(obj)
+	return found
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(strings.TrimSpace(path), pathSeparator)
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, pathSeparator)
+}
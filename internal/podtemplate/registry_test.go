@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package podtemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"hiro.io/anyapplication/internal/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newObject(apiVersion string, kind string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	return obj
+}
+
+func TestRegistry_BuiltinKinds(t *testing.T) {
+	registry := DefaultRegistry()
+
+	path, found := registry.TemplatePath(newObject("batch/v1", "CronJob"))
+	assert.True(t, found)
+	assert.Equal(t, []string{"spec", "jobTemplate", "spec", "template"}, path)
+
+	path, found = registry.PodSpecPath(newObject("apps/v1", "ReplicaSet"))
+	assert.True(t, found)
+	assert.Equal(t, []string{"spec", "template", "spec"}, path)
+
+	path, found = registry.PodSpecPath(newObject("v1", "Pod"))
+	assert.True(t, found)
+	assert.Equal(t, []string{"spec"}, path)
+
+	assert.False(t, registry.IsWorkload(newObject("v1", "ConfigMap")))
+	assert.False(t, registry.IsWorkload(newObject("example.com/v1", "Deployment")))
+}
+
+func TestRegistry_ConfiguredKinds(t *testing.T) {
+	registry, err := NewRegistry([]config.PodTemplateConfig{
+		{Group: "example.com", Kind: "Worker", Path: "spec.worker.template"},
+	})
+	assert.NoError(t, err)
+
+	obj := newObject("example.com/v1alpha1", "Worker")
+	obj.Object["spec"] = map[string]interface{}{
+		"worker": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": []interface{}{}},
+			},
+		},
+	}
+	template, found := registry.PodTemplate(obj)
+	assert.True(t, found)
+	assert.Equal(t, map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{}}}, template)
+	assert.True(t, registry.IsWorkload(newObject("apps/v1", "Deployment")))
+}
+
+func TestRegistry_InvalidConfiguration(t *testing.T) {
+	_, err := NewRegistry([]config.PodTemplateConfig{{Group: "example.com", Kind: "Worker"}})
+	assert.Error(t, err)
+
+	_, err = NewRegistry([]config.PodTemplateConfig{{Group: "example.com", Path: "spec.template"}})
+	assert.Error(t, err)
+}
@@ -10,30 +10,51 @@ import (
 	v1 "hiro.io/anyapplication/api/v1"
 	"hiro.io/anyapplication/internal/controller/types"
 	"hiro.io/anyapplication/internal/httpapi/api"
+	"hiro.io/anyapplication/internal/podtemplate"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type applicationSpecs struct {
 	applications types.Applications
 	kubeClient   client.Client
+	podTemplates *podtemplate.Registry
 	log          logr.Logger
 }
 
-func NewApplicationSpecs(applications types.Applications, kubeClient client.Client, log logr.Logger) api.ApplicationSpecs {
+func NewApplicationSpecs(
+	applications types.Applications,
+	kubeClient client.Client,
+	podTemplates *podtemplate.Registry,
+	log logr.Logger,
+) api.ApplicationSpecs {
 	return &applicationSpecs{
 		applications: applications,
 		kubeClient:   kubeClient,
+		podTemplates: podTemplates,
 		log:          log,
 	}
 }
 
 func (s *applicationSpecs) GetApplicationSpec(ctx context.Context, application *v1.AnyApplication) (*api.ApplicationSpec, error) {
 
-	chart, err := s.applications.GetRenderedChart(application)
+	version, err := s.applications.DetermineTargetVersion(application)
 	if err != nil {
 		return nil, err
 	}
+	chart, err := s.applications.GetRenderedChart(ctx, application, version)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &ClusterInfo{}
+	nodes := &corev1.NodeList{}
+	if err := s.kubeClient.List(ctx, nodes); err != nil {
+		s.log.Error(err, "Failed to list nodes, assuming a single node")
+	} else {
+		cluster.Nodes = nodes.Items
+	}
 
-	specParser := NewSpecParser(application.Name, application.Namespace, chart.Resources)
+	specParser := NewSpecParser(application.Name, application.Namespace, chart.Resources, s.podTemplates, cluster)
 	return specParser.Parse()
 }
@@ -0,0 +1,414 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/httpapi/api"
+	"hiro.io/anyapplication/internal/podtemplate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	podsResource       = "pods"
+	claimsResource     = "persistentvolumeclaims"
+	storageClassSuffix = ".storageclass.storage.k8s.io/"
+)
+
+// resources which quotas may limit without the requests. prefix
+var standardResources = map[string]bool{
+	string(corev1.ResourceCPU):              true,
+	string(corev1.ResourceMemory):           true,
+	string(corev1.ResourceEphemeralStorage): true,
+}
+
+// InsufficientCapacityError reports the resources the zone cannot provide
+type InsufficientCapacityError struct {
+	Shortfalls []string
+}
+
+func (e *InsufficientCapacityError) Error() string {
+	return "Insufficient zone capacity: " + strings.Join(e.Shortfalls, "; ")
+}
+
+// Demand is the amount of resources an application requires in a zone
+type Demand struct {
+	// Requests of all pods
+	Requests corev1.ResourceList
+	// LargestPod are the requests of the largest pod, at least one node has to fit it
+	LargestPod corev1.ResourceList
+	// Quota usage of the application per namespace
+	Quota map[string]corev1.ResourceList
+}
+
+// NewDemand sums up the resources of an application spec
+func NewDemand(spec *api.ApplicationSpec) (*Demand, error) {
+	demand := &Demand{
+		Requests:   corev1.ResourceList{},
+		LargestPod: corev1.ResourceList{},
+		Quota:      map[string]corev1.ResourceList{},
+	}
+	for _, item := range spec.Resources {
+		claim, err := isPVCResources(item)
+		if err != nil {
+			return nil, err
+		}
+		if claim {
+			pvc, err := item.AsPVCResources()
+			if err != nil {
+				return nil, err
+			}
+			if err := demand.addClaim(pvc); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		pod, err := item.AsPodResources()
+		if err != nil {
+			return nil, err
+		}
+		if err := demand.addPod(pod); err != nil {
+			return nil, err
+		}
+	}
+	return demand, nil
+}
+
+// isPVCResources tells the items of the union apart, only claim resources carry a storage class
+func isPVCResources(item api.ApplicationSpec_Resources_Item) (bool, error) {
+	raw, err := item.MarshalJSON()
+	if err != nil {
+		return false, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false, err
+	}
+	_, found := fields["storage-class"]
+	return found, nil
+}
+
+func (d *Demand) addPod(pod api.PodResources) error {
+	requests, err := parseQuantities(pod.Requests)
+	if err != nil {
+		return err
+	}
+	limits, err := parseQuantities(pod.Limits)
+	if err != nil {
+		return err
+	}
+	replicas := int64(pod.Replica)
+	for name, quantity := range requests {
+		addTo(d.Requests, name, multiply(quantity, replicas))
+		if current, exists := d.LargestPod[corev1.ResourceName(name)]; !exists || current.Cmp(quantity) < 0 {
+			d.LargestPod[corev1.ResourceName(name)] = quantity.DeepCopy()
+		}
+	}
+	addTo(d.Requests, podsResource, *resource.NewQuantity(replicas, resource.DecimalSI))
+	addPodQuotaUsage(d.quotaOf(pod.Id.Namespace), requests, limits, replicas)
+	return nil
+}
+
+// addPodQuotaUsage adds the usage of pods to the quota resources they count towards
+func addPodQuotaUsage(quota corev1.ResourceList, requests, limits map[string]resource.Quantity, replicas int64) {
+	for name, quantity := range requests {
+		total := multiply(quantity, replicas)
+		addTo(quota, "requests."+name, total)
+		if standardResources[name] {
+			addTo(quota, name, total)
+		}
+	}
+	for name, quantity := range limits {
+		addTo(quota, "limits."+name, multiply(quantity, replicas))
+	}
+	addTo(quota, podsResource, *resource.NewQuantity(replicas, resource.DecimalSI))
+}
+
+func (d *Demand) addClaim(pvc api.PVCResources) error {
+	quota := d.quotaOf(pvc.Id.Namespace)
+	requests, err := parseQuantities(pvc.Requests)
+	if err != nil {
+		return err
+	}
+	replicas := int64(pvc.Replica)
+	claims := *resource.NewQuantity(replicas, resource.DecimalSI)
+	addTo(quota, claimsResource, claims)
+	if pvc.StorageClass != "" {
+		addTo(quota, pvc.StorageClass+storageClassSuffix+claimsResource, claims)
+	}
+	if storage, found := requests[string(corev1.ResourceStorage)]; found {
+		total := multiply(storage, replicas)
+		addTo(quota, string(corev1.ResourceRequestsStorage), total)
+		if pvc.StorageClass != "" {
+			addTo(quota, pvc.StorageClass+storageClassSuffix+string(corev1.ResourceRequestsStorage), total)
+		}
+	}
+	return nil
+}
+
+func (d *Demand) quotaOf(namespace string) corev1.ResourceList {
+	quota, exists := d.Quota[namespace]
+	if !exists {
+		quota = corev1.ResourceList{}
+		d.Quota[namespace] = quota
+	}
+	return quota
+}
+
+// Namespaces returns the namespaces the application creates resources in
+func (d *Demand) Namespaces() []string {
+	namespaces := make([]string, 0, len(d.Quota))
+	for namespace := range d.Quota {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// CheckNodes compares the demand with the allocatable capacity of schedulable nodes
+// minus the requests of the pods running on them. Without nodes the check is skipped.
+func (d *Demand) CheckNodes(nodes []corev1.Node, pods []corev1.Pod) []string {
+	if len(nodes) == 0 {
+		return nil
+	}
+	free := make(map[string]corev1.ResourceList, len(nodes))
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		allocatable := node.Status.Allocatable.DeepCopy()
+		if allocatable == nil {
+			allocatable = corev1.ResourceList{}
+		}
+		free[node.Name] = allocatable
+	}
+	for _, pod := range pods {
+		nodeFree, exists := free[pod.Spec.NodeName]
+		if !exists || isTerminated(&pod) {
+			continue
+		}
+		requests, _ := podResources(&pod)
+		for name, quantity := range requests {
+			subtractFrom(nodeFree, corev1.ResourceName(name), quantity)
+		}
+		subtractFrom(nodeFree, podsResource, *resource.NewQuantity(1, resource.DecimalSI))
+	}
+
+	total := corev1.ResourceList{}
+	largestPodFits := false
+	for _, nodeFree := range free {
+		for name, quantity := range nodeFree {
+			if quantity.Sign() > 0 {
+				addTo(total, string(name), quantity)
+			}
+		}
+		largestPodFits = largestPodFits || fits(d.LargestPod, nodeFree)
+	}
+
+	shortfalls := make([]string, 0)
+	for _, name := range sortedNames(d.Requests) {
+		requested := d.Requests[name]
+		available := total[name]
+		if available.Cmp(requested) < 0 {
+			shortfalls = append(shortfalls, fmt.Sprintf("%s requested %s, available %s",
+				name, requested.String(), available.String()))
+		}
+	}
+	if len(shortfalls) == 0 && !largestPodFits {
+		shortfalls = append(shortfalls, "no node fits a pod requesting "+formatResources(d.LargestPod))
+	}
+	return shortfalls
+}
+
+// CheckQuotas compares the demand with the remaining resource quotas of the namespace.
+// Released is the quota usage freed by the deployment, i.e. the pods it replaces.
+func (d *Demand) CheckQuotas(namespace string, quotas []corev1.ResourceQuota, released corev1.ResourceList) []string {
+	usage := d.Quota[namespace]
+	shortfalls := make([]string, 0)
+	for _, quota := range quotas {
+		for _, name := range sortedNames(quota.Status.Hard) {
+			requested, found := usage[name]
+			if !found || requested.IsZero() {
+				continue
+			}
+			remaining := quota.Status.Hard[name].DeepCopy()
+			if used, found := quota.Status.Used[name]; found {
+				remaining.Sub(used)
+			}
+			if freed, found := released[name]; found {
+				remaining.Add(freed)
+			}
+			if remaining.Cmp(requested) < 0 {
+				shortfalls = append(shortfalls, fmt.Sprintf("%s requested %s, quota %s/%s remaining %s",
+					name, requested.String(), namespace, quota.Name, remaining.String()))
+			}
+		}
+	}
+	return shortfalls
+}
+
+// CapacityChecker verifies that the zone can fit an application before it is deployed
+type CapacityChecker struct {
+	kubeClient    client.Client
+	podTemplates  *podtemplate.Registry
+	instanceLabel string
+}
+
+// NewCapacityChecker creates a checker which recognizes the pods of an instance by the instance label
+func NewCapacityChecker(kubeClient client.Client, podTemplates *podtemplate.Registry, instanceLabel string) *CapacityChecker {
+	return &CapacityChecker{
+		kubeClient:    kubeClient,
+		podTemplates:  podTemplates,
+		instanceLabel: instanceLabel,
+	}
+}
+
+// Check returns an InsufficientCapacityError when the rendered chart does not fit the nodes
+// or the resource quotas of the zone. Pods of the instance itself are not counted as used
+// capacity, they are replaced by the deployment.
+func (c *CapacityChecker) Check(ctx context.Context, chart *types.RenderedChart) error {
+	nodes := &corev1.NodeList{}
+	if err := c.kubeClient.List(ctx, nodes); err != nil {
+		return errors.Wrap(err, "Failed to list nodes")
+	}
+	cluster := &ClusterInfo{Nodes: nodes.Items}
+
+	specParser := NewSpecParser(chart.Instance.Name, chart.Instance.Namespace, chart.Resources, c.podTemplates, cluster)
+	spec, err := specParser.Parse()
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse application resources")
+	}
+	demand, err := NewDemand(spec)
+	if err != nil {
+		return errors.Wrap(err, "Failed to sum up application resources")
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.kubeClient.List(ctx, pods); err != nil {
+		return errors.Wrap(err, "Failed to list pods")
+	}
+	otherPods := make([]corev1.Pod, 0, len(pods.Items))
+	released := map[string]corev1.ResourceList{}
+	for _, pod := range pods.Items {
+		if chart.Instance.InstanceId == "" || pod.Labels[c.instanceLabel] != chart.Instance.InstanceId {
+			otherPods = append(otherPods, pod)
+			continue
+		}
+		if isTerminated(&pod) {
+			continue
+		}
+		if _, exists := released[pod.Namespace]; !exists {
+			released[pod.Namespace] = corev1.ResourceList{}
+		}
+		requests, limits := podResources(&pod)
+		addPodQuotaUsage(released[pod.Namespace], requests, limits, 1)
+	}
+	shortfalls := demand.CheckNodes(nodes.Items, otherPods)
+
+	for _, namespace := range demand.Namespaces() {
+		quotas := &corev1.ResourceQuotaList{}
+		if err := c.kubeClient.List(ctx, quotas, client.InNamespace(namespace)); err != nil {
+			return errors.Wrapf(err, "Failed to list resource quotas of namespace %s", namespace)
+		}
+		shortfalls = append(shortfalls, demand.CheckQuotas(namespace, quotas.Items, released[namespace])...)
+	}
+
+	if len(shortfalls) > 0 {
+		return &InsufficientCapacityError{Shortfalls: shortfalls}
+	}
+	return nil
+}
+
+// podResources returns the effective requests and limits of a running pod
+func podResources(pod *corev1.Pod) (map[string]resource.Quantity, map[string]resource.Quantity) {
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&pod.Spec)
+	if err != nil {
+		return nil, nil
+	}
+	collected, err := CollectPodResources(spec, 1, pod.Name, pod.Namespace)
+	if err != nil {
+		return nil, nil
+	}
+	requests, err := parseQuantities(collected.Requests)
+	if err != nil {
+		return nil, nil
+	}
+	limits, err := parseQuantities(collected.Limits)
+	if err != nil {
+		return requests, nil
+	}
+	return requests, limits
+}
+
+func isTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func fits(requests corev1.ResourceList, free corev1.ResourceList) bool {
+	for name, quantity := range requests {
+		available := free[name]
+		if available.Cmp(quantity) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func parseQuantities(values map[string]string) (map[string]resource.Quantity, error) {
+	quantities := make(map[string]resource.Quantity, len(values))
+	for name, value := range values {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse quantity of %s", name)
+		}
+		quantities[name] = quantity
+	}
+	return quantities, nil
+}
+
+func multiply(quantity resource.Quantity, times int64) resource.Quantity {
+	result := quantity.DeepCopy()
+	result.Mul(times)
+	return result
+}
+
+func addTo(list corev1.ResourceList, name string, quantity resource.Quantity) {
+	current := list[corev1.ResourceName(name)]
+	current.Add(quantity)
+	list[corev1.ResourceName(name)] = current
+}
+
+func subtractFrom(list corev1.ResourceList, name corev1.ResourceName, quantity resource.Quantity) {
+	current := list[name]
+	current.Sub(quantity)
+	list[name] = current
+}
+
+func sortedNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func formatResources(list corev1.ResourceList) string {
+	values := make([]string, 0, len(list))
+	for _, name := range sortedNames(list) {
+		quantity := list[name]
+		values = append(values, string(name)+"="+quantity.String())
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"hiro.io/anyapplication/internal/controller/types"
+	"hiro.io/anyapplication/internal/httpapi/api"
+	"hiro.io/anyapplication/internal/podtemplate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func newNode(name string, cpu string, memory string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+		},
+	}
+}
+
+func newPod(name string, nodeName string, cpu string, memory string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func newDemand(t *testing.T, pods []api.PodResources, claims []api.PVCResources) *Demand {
+	items := make([]api.ApplicationSpec_Resources_Item, 0)
+	for _, pod := range pods {
+		item := api.ApplicationSpec_Resources_Item{}
+		assert.NoError(t, item.FromPodResources(pod))
+		items = append(items, item)
+	}
+	for _, claim := range claims {
+		item := api.ApplicationSpec_Resources_Item{}
+		assert.NoError(t, item.FromPVCResources(claim))
+		items = append(items, item)
+	}
+	demand, err := NewDemand(&api.ApplicationSpec{Resources: items})
+	assert.NoError(t, err)
+	return demand
+}
+
+func TestDemand_SumsPodsAndClaims(t *testing.T) {
+	demand := newDemand(t,
+		[]api.PodResources{{
+			Id:       api.ResourceId{Name: "app", Namespace: "ns"},
+			Replica:  3,
+			Requests: map[string]string{"cpu": "500m", "memory": "1Gi"},
+			Limits:   map[string]string{"cpu": "1"},
+		}},
+		[]api.PVCResources{{
+			Id:           api.ResourceId{Name: "data", Namespace: "ns"},
+			Replica:      3,
+			Requests:     map[string]string{"storage": "10Gi"},
+			StorageClass: "fast",
+		}},
+	)
+
+	assert.Equal(t, "1500m", demand.Requests.Cpu().String())
+	assert.Equal(t, "3Gi", demand.Requests.Memory().String())
+	assert.Equal(t, "3", demand.Requests.Pods().String())
+	assert.Equal(t, "500m", demand.LargestPod.Cpu().String())
+
+	quota := demand.Quota["ns"]
+	expected := map[corev1.ResourceName]string{
+		"requests.cpu":           "1500m",
+		"cpu":                    "1500m",
+		"limits.cpu":             "3",
+		"requests.memory":        "3Gi",
+		"memory":                 "3Gi",
+		"pods":                   "3",
+		"persistentvolumeclaims": "3",
+		"requests.storage":       "30Gi",
+		"fast.storageclass.storage.k8s.io/persistentvolumeclaims": "3",
+		"fast.storageclass.storage.k8s.io/requests.storage":       "30Gi",
+	}
+	assert.Len(t, quota, len(expected))
+	for name, value := range expected {
+		quantity := quota[name]
+		assert.Equal(t, value, quantity.String(), string(name))
+	}
+	assert.Equal(t, []string{"ns"}, demand.Namespaces())
+}
+
+func TestDemand_CheckNodes(t *testing.T) {
+	demand := newDemand(t, []api.PodResources{{
+		Id:       api.ResourceId{Name: "app", Namespace: "ns"},
+		Replica:  2,
+		Requests: map[string]string{"cpu": "1", "memory": "1Gi"},
+	}}, nil)
+	nodes := []corev1.Node{newNode("node-1", "2", "4Gi"), newNode("node-2", "2", "4Gi")}
+
+	assert.Empty(t, demand.CheckNodes(nodes, nil))
+
+	pods := []corev1.Pod{
+		newPod("busy-1", "node-1", "1500m", "1Gi"),
+		newPod("busy-2", "node-2", "1", "1Gi"),
+	}
+	assert.Equal(t, []string{"cpu requested 2, available 1500m"}, demand.CheckNodes(nodes, pods))
+
+	// terminated pods and pods of other nodes free their requests
+	succeeded := newPod("done", "node-1", "1500m", "1Gi")
+	succeeded.Status.Phase = corev1.PodSucceeded
+	pods = []corev1.Pod{succeeded, newPod("elsewhere", "node-3", "2", "1Gi")}
+	assert.Empty(t, demand.CheckNodes(nodes, pods))
+
+	// unschedulable nodes provide no capacity
+	cordoned := newNode("node-2", "2", "4Gi")
+	cordoned.Spec.Unschedulable = true
+	assert.Empty(t, demand.CheckNodes([]corev1.Node{newNode("node-1", "2", "4Gi"), cordoned}, nil))
+	assert.Equal(t, []string{"cpu requested 2, available 1"},
+		demand.CheckNodes([]corev1.Node{newNode("node-1", "1", "4Gi"), cordoned}, nil))
+
+	// without node information the check is skipped
+	assert.Empty(t, demand.CheckNodes(nil, nil))
+}
+
+func TestDemand_CheckNodes_LargestPod(t *testing.T) {
+	demand := newDemand(t, []api.PodResources{{
+		Id:       api.ResourceId{Name: "app", Namespace: "ns"},
+		Replica:  1,
+		Requests: map[string]string{"cpu": "3"},
+	}}, nil)
+	nodes := []corev1.Node{newNode("node-1", "2", "4Gi"), newNode("node-2", "2", "4Gi")}
+
+	assert.Equal(t, []string{"no node fits a pod requesting cpu=3"}, demand.CheckNodes(nodes, nil))
+}
+
+func TestDemand_CheckNodes_ExtendedResources(t *testing.T) {
+	demand := newDemand(t, []api.PodResources{{
+		Id:       api.ResourceId{Name: "app", Namespace: "ns"},
+		Replica:  1,
+		Requests: map[string]string{"nvidia.com/gpu": "1"},
+	}}, nil)
+	nodes := []corev1.Node{newNode("node-1", "2", "4Gi")}
+
+	assert.Equal(t, []string{"nvidia.com/gpu requested 1, available 0"}, demand.CheckNodes(nodes, nil))
+}
+
+func TestDemand_CheckQuotas(t *testing.T) {
+	demand := newDemand(t, []api.PodResources{{
+		Id:       api.ResourceId{Name: "app", Namespace: "ns"},
+		Replica:  2,
+		Requests: map[string]string{"cpu": "500m", "memory": "256Mi"},
+	}}, nil)
+	quota := corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "ns"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				"requests.cpu":    resource.MustParse("2"),
+				"requests.memory": resource.MustParse("1Gi"),
+				"pods":            resource.MustParse("10"),
+				"services":        resource.MustParse("1"),
+			},
+			Used: corev1.ResourceList{
+				"requests.cpu":    resource.MustParse("1500m"),
+				"requests.memory": resource.MustParse("256Mi"),
+				"pods":            resource.MustParse("9"),
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"pods requested 2, quota ns/compute remaining 1",
+		"requests.cpu requested 1, quota ns/compute remaining 500m",
+	}, demand.CheckQuotas("ns", []corev1.ResourceQuota{quota}, nil))
+
+	// usage of the replaced pods is released
+	released := corev1.ResourceList{
+		"requests.cpu": resource.MustParse("500m"),
+		"pods":         resource.MustParse("1"),
+	}
+	assert.Empty(t, demand.CheckQuotas("ns", []corev1.ResourceQuota{quota}, released))
+
+	assert.Empty(t, demand.CheckQuotas("other", []corev1.ResourceQuota{quota}, nil))
+}
+
+func TestCapacityChecker_Check(t *testing.T) {
+	deploymentYaml := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: ns
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: app
+          resources:
+            requests:
+              cpu: "1"
+              memory: "1Gi"
+`
+	deployment := &unstructured.Unstructured{}
+	assert.NoError(t, yaml.Unmarshal([]byte(deploymentYaml), deployment))
+	chart := &types.RenderedChart{
+		Instance:  types.ApplicationInstance{InstanceId: "app-ns", Name: "app", Namespace: "ns"},
+		Resources: []*unstructured.Unstructured{deployment},
+	}
+
+	node := newNode("node-1", "2", "4Gi")
+	otherPod := newPod("other", "node-1", "500m", "512Mi")
+	ownPod := newPod("own", "node-1", "1", "1Gi")
+	ownPod.Labels = map[string]string{"example.com/instance": "app-ns"}
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "ns"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{"requests.memory": resource.MustParse("2Gi")},
+			Used: corev1.ResourceList{"requests.memory": resource.MustParse("1Gi")},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	// the own pod is replaced, it neither occupies the node nor the quota
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(&node, &ownPod, quota).Build()
+	checker := NewCapacityChecker(kubeClient, podtemplate.DefaultRegistry(), "example.com/instance")
+	assert.NoError(t, checker.Check(context.TODO(), chart))
+
+	kubeClient = fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(&node, &ownPod, &otherPod, quota).Build()
+	checker = NewCapacityChecker(kubeClient, podtemplate.DefaultRegistry(), "example.com/instance")
+	err := checker.Check(context.TODO(), chart)
+
+	var capacityErr *InsufficientCapacityError
+	assert.True(t, errors.As(err, &capacityErr))
+	assert.Equal(t, []string{"cpu requested 2, available 1500m"}, capacityErr.Shortfalls)
+	assert.Equal(t, "Insufficient zone capacity: cpu requested 2, available 1500m", err.Error())
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ClusterInfo is the cluster state estimations depend on
+type ClusterInfo struct {
+	Nodes []corev1.Node
+}
+
+// CountNodes returns the number of schedulable nodes matching the node selector.
+// Without node information a single node is assumed.
+func (c *ClusterInfo) CountNodes(nodeSelector map[string]string) int32 {
+	if c == nil || len(c.Nodes) == 0 {
+		return 1
+	}
+	selector := labels.SelectorFromSet(nodeSelector)
+	count := int32(0)
+	for _, node := range c.Nodes {
+		if !node.Spec.Unschedulable && selector.Matches(labels.Set(node.Labels)) {
+			count++
+		}
+	}
+	return count
+}
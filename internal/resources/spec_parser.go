@@ -7,27 +7,39 @@ import (
 	"strings"
 
 	"hiro.io/anyapplication/internal/httpapi/api"
+	"hiro.io/anyapplication/internal/podtemplate"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 type ApplicationSpecParser struct {
-	name      string
-	namespace string
-	resources []*unstructured.Unstructured
+	name         string
+	namespace    string
+	resources    []*unstructured.Unstructured
+	podTemplates *podtemplate.Registry
+	cluster      *ClusterInfo
 }
 
-func NewSpecParser(name string, namespace string, resources []*unstructured.Unstructured) *ApplicationSpecParser {
+func NewSpecParser(
+	name string,
+	namespace string,
+	resources []*unstructured.Unstructured,
+	podTemplates *podtemplate.Registry,
+	cluster *ClusterInfo,
+) *ApplicationSpecParser {
 	return &ApplicationSpecParser{
-		name:      name,
-		namespace: namespace,
-		resources: resources,
+		name:         name,
+		namespace:    namespace,
+		resources:    resources,
+		podTemplates: podTemplates,
+		cluster:      cluster,
 	}
 }
 
 func (p *ApplicationSpecParser) Parse() (*api.ApplicationSpec, error) {
+	maxReplicas := p.collectMaxReplicas()
 	resources := make([]api.ApplicationSpec_Resources_Item, 0)
 	for _, resource := range p.resources {
-		extracted, err := p.extractSpec(resource)
+		extracted, err := p.extractSpec(resource, maxReplicas)
 		if err != nil {
 			return nil, err
 		}
@@ -43,44 +55,92 @@ func (p *ApplicationSpecParser) Parse() (*api.ApplicationSpec, error) {
 	return &spec, nil
 }
 
-func (p *ApplicationSpecParser) extractSpec(u *unstructured.Unstructured) ([]api.ApplicationSpec_Resources_Item, error) {
+// collectMaxReplicas returns the maximum replicas of workloads scaled by a HorizontalPodAutoscaler
+func (p *ApplicationSpecParser) collectMaxReplicas() map[scaleTarget]int32 {
+	maxReplicas := make(map[scaleTarget]int32)
+	for _, resource := range p.resources {
+		if resource.GetKind() != "HorizontalPodAutoscaler" {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(resource.Object, "spec", "scaleTargetRef", "kind")
+		name, _, _ := unstructured.NestedString(resource.Object, "spec", "scaleTargetRef", "name")
+		replicas, found := nestedInt(resource.Object, "spec", "maxReplicas")
+		if found {
+			maxReplicas[scaleTarget{kind: kind, name: name}] = int32(replicas)
+		}
+	}
+	return maxReplicas
+}
+
+func (p *ApplicationSpecParser) extractSpec(
+	u *unstructured.Unstructured,
+	maxReplicas map[scaleTarget]int32,
+) ([]api.ApplicationSpec_Resources_Item, error) {
 	kind := u.GetKind()
 	resourceItems := make([]api.ApplicationSpec_Resources_Item, 0)
+	if p.podTemplates.IsWorkload(u) {
+		return p.extractWorkloadSpec(u, maxReplicas)
+	}
 	switch strings.ToLower(kind) {
-	case "pvc":
+	case "persistentvolumeclaim":
 		est := NewPVCParser()
 		pvcResources, err := est.Parse(u)
 		if err != nil {
 			return nil, err
 		}
+		if pvcResources == nil {
+			break
+		}
 		item := api.ApplicationSpec_Resources_Item{}
 		if err := item.FromPVCResources(*pvcResources); err != nil {
 			return nil, err
 		}
 		resourceItems = append(resourceItems, item)
-	case "deployment", "statefulset", "job", "daemonset":
-		est := NewWorkloadParser()
-		podResources, pvcResources, err := est.Parse(u)
-		if err != nil {
-			return nil, err
+	default:
+
+	}
+	return resourceItems, nil
+}
+
+func (p *ApplicationSpecParser) extractWorkloadSpec(
+	u *unstructured.Unstructured,
+	maxReplicas map[scaleTarget]int32,
+) ([]api.ApplicationSpec_Resources_Item, error) {
+	resourceItems := make([]api.ApplicationSpec_Resources_Item, 0)
+	est := NewWorkloadParser(p.podTemplates, p.cluster)
+	podResources, pvcResources, err := est.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	// autoscaled workloads may grow up to the maximum replicas of the autoscaler
+	if replicas, found := maxReplicas[scaleTarget{kind: u.GetKind(), name: u.GetName()}]; found {
+		if podResources != nil && podResources.Replica < replicas {
+			podResources.Replica = replicas
 		}
-		if podResources != nil {
-			item := api.ApplicationSpec_Resources_Item{}
-			if err := item.FromPodResources(*podResources); err != nil {
-				return nil, err
+		for i := range pvcResources {
+			if pvcResources[i].Replica < replicas {
+				pvcResources[i].Replica = replicas
 			}
-			resourceItems = append(resourceItems, item)
 		}
-		for _, pvcResource := range pvcResources {
-			item := api.ApplicationSpec_Resources_Item{}
-			if err := item.FromPVCResources(pvcResource); err != nil {
-				return nil, err
-			}
-			resourceItems = append(resourceItems, item)
+	}
+	if podResources != nil {
+		item := api.ApplicationSpec_Resources_Item{}
+		if err := item.FromPodResources(*podResources); err != nil {
+			return nil, err
 		}
-
-	default:
-
+		resourceItems = append(resourceItems, item)
+	}
+	for _, pvcResource := range pvcResources {
+		item := api.ApplicationSpec_Resources_Item{}
+		if err := item.FromPVCResources(pvcResource); err != nil {
+			return nil, err
+		}
+		resourceItems = append(resourceItems, item)
 	}
 	return resourceItems, nil
 }
+
+type scaleTarget struct {
+	kind string
+	name string
+}
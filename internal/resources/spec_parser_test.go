@@ -9,6 +9,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"hiro.io/anyapplication/internal/controller/fixture"
 	"hiro.io/anyapplication/internal/httpapi/api"
+	"hiro.io/anyapplication/internal/podtemplate"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
 )
 
 func TestParseSpec_Nginx(t *testing.T) {
@@ -16,7 +19,7 @@ func TestParseSpec_Nginx(t *testing.T) {
 	resources := fixture.LoadYamlFixture("nginx.yaml")
 	expected := fixture.LoadJSONFixture[api.ApplicationSpec]("nginx-spec.json")
 
-	est := NewSpecParser("nginx", "default", resources)
+	est := NewSpecParser("nginx", "default", resources, podtemplate.DefaultRegistry(), nil)
 	actual, err := est.Parse()
 	assert.NoError(t, err)
 
@@ -28,9 +31,78 @@ func TestParseSpec_Kafka(t *testing.T) {
 	resources := fixture.LoadYamlFixture("kafka.yaml")
 	expected := fixture.LoadJSONFixture[api.ApplicationSpec]("kafka-spec.json")
 
-	est := NewSpecParser("kafka", "default", resources)
+	est := NewSpecParser("kafka", "default", resources, podtemplate.DefaultRegistry(), nil)
 	actual, err := est.Parse()
 	assert.NoError(t, err)
 
 	assert.Equal(t, &expected, actual)
 }
+
+func TestParseSpec_AutoscalerAndStandalonePVC(t *testing.T) {
+	resources := make([]*unstructured.Unstructured, 0)
+	for _, doc := range []string{`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+        - name: web
+          resources:
+            requests:
+              cpu: 100m
+`, `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+  namespace: default
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+  minReplicas: 2
+  maxReplicas: 5
+`, `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: shared
+  namespace: default
+spec:
+  storageClassName: local
+  resources:
+    requests:
+      storage: 5Gi
+`} {
+		u := &unstructured.Unstructured{}
+		assert.NoError(t, yaml.Unmarshal([]byte(doc), u))
+		resources = append(resources, u)
+	}
+
+	est := NewSpecParser("web", "default", resources, podtemplate.DefaultRegistry(), nil)
+	actual, err := est.Parse()
+	assert.NoError(t, err)
+	assert.Len(t, actual.Resources, 2)
+
+	podResources, err := actual.Resources[0].AsPodResources()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), podResources.Replica)
+	assert.Equal(t, map[string]string{"cpu": "100m"}, podResources.Requests)
+
+	pvcResources, err := actual.Resources[1].AsPVCResources()
+	assert.NoError(t, err)
+	assert.Equal(t, api.PVCResources{
+		Id:           api.ResourceId{Name: "shared", Namespace: "default"},
+		Limits:       map[string]string{},
+		Replica:      1,
+		Requests:     map[string]string{"storage": "5Gi"},
+		StorageClass: "local",
+	}, pvcResources)
+}
+
@@ -5,9 +5,11 @@ package resources
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"hiro.io/anyapplication/internal/httpapi/api"
+	"hiro.io/anyapplication/internal/podtemplate"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -23,16 +25,18 @@ type ResourceTotals struct {
 	Limits   ResourceValues
 }
 
-type WorkloadParser struct{}
+type WorkloadParser struct {
+	podTemplates *podtemplate.Registry
+	cluster      *ClusterInfo
+}
 
-func NewWorkloadParser() *WorkloadParser {
-	return &WorkloadParser{}
+func NewWorkloadParser(podTemplates *podtemplate.Registry, cluster *ClusterInfo) *WorkloadParser {
+	return &WorkloadParser{podTemplates: podTemplates, cluster: cluster}
 }
 
 func (re *WorkloadParser) Parse(obj *unstructured.Unstructured) (*api.PodResources, []api.PVCResources, error) {
 	name := obj.GetName()
 	namespace := obj.GetNamespace()
-	kind := obj.GetKind()
 	spec, specFound, err := unstructured.NestedMap(obj.Object, "spec")
 	if err != nil {
 		return nil, nil, err
@@ -41,18 +45,12 @@ func (re *WorkloadParser) Parse(obj *unstructured.Unstructured) (*api.PodResourc
 		return nil, nil, nil
 	}
 
-	replicas := int32(1)
-	if kind == "Deployment" || kind == "StatefulSet" {
-		if r, found, _ := unstructured.NestedInt64(spec, "replicas"); found {
-			replicas = int32(r)
-		}
-	}
 	templateSpec := spec
-	if tmpl, ok := spec["template"]; ok {
-		if tmplMap, ok := tmpl.(map[string]interface{}); ok {
-			templateSpec, _, _ = unstructured.NestedMap(tmplMap, "spec")
-		}
+	if template, found := re.podTemplates.PodTemplate(obj); found {
+		templateSpec, _, _ = unstructured.NestedMap(template, "spec")
 	}
+	replicas := re.getReplicas(obj.GetKind(), spec, templateSpec)
+
 	podResources, err := CollectPodResources(templateSpec, replicas, name, namespace)
 	if err != nil {
 		return nil, nil, err
@@ -73,6 +71,55 @@ func (re *WorkloadParser) Parse(obj *unstructured.Unstructured) (*api.PodResourc
 	return podResources, pvcResources, nil
 }
 
+// getReplicas returns the number of pods the workload runs at the same time
+func (re *WorkloadParser) getReplicas(kind string, spec map[string]interface{}, templateSpec map[string]interface{}) int32 {
+	switch kind {
+	case "Pod":
+		return 1
+	case "DaemonSet":
+		nodeSelector, _, _ := unstructured.NestedStringMap(templateSpec, "nodeSelector")
+		return re.cluster.CountNodes(nodeSelector)
+	case "Job":
+		return getJobReplicas(spec)
+	case "CronJob":
+		jobSpec, _, _ := unstructured.NestedMap(spec, "jobTemplate", "spec")
+		return getJobReplicas(jobSpec)
+	default:
+		if r, found := nestedInt(spec, "replicas"); found {
+			return int32(r)
+		}
+		return 1
+	}
+}
+
+// getJobReplicas returns the parallelism of the job limited by its completions
+func getJobReplicas(jobSpec map[string]interface{}) int32 {
+	parallelism := int64(1)
+	if p, found := nestedInt(jobSpec, "parallelism"); found {
+		parallelism = p
+	}
+	if completions, found := nestedInt(jobSpec, "completions"); found && completions < parallelism {
+		parallelism = completions
+	}
+	return int32(parallelism)
+}
+
+// nestedInt reads integers of objects decoded as YAML (int64) as well as JSON (float64)
+func nestedInt(obj map[string]interface{}, fields ...string) (int64, bool) {
+	value, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil || !found {
+		return 0, false
+	}
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
 func CollectPVCResources(volumeClaimTemplateSpecs []interface{}, replicas int32) ([]api.PVCResources, error) {
 	if volumeClaimTemplateSpecs == nil {
 		return nil, nil
@@ -85,7 +132,7 @@ func CollectPVCResources(volumeClaimTemplateSpecs []interface{}, replicas int32)
 			if err != nil {
 				return nil, err
 			}
-			parsed.Replica = 3
+			parsed.Replica = replicas
 			pvcResources = append(pvcResources, *parsed)
 		}
 	}
@@ -93,47 +140,136 @@ func CollectPVCResources(volumeClaimTemplateSpecs []interface{}, replicas int32)
 	return pvcResources, nil
 }
 
+// CollectPodResources returns the effective requests and limits of a single pod.
+// Init containers run one after another, so a pod needs the maximum of any init container
+// and the sum of its containers. Sidecars (init containers with restartPolicy Always)
+// keep running next to later init containers and containers. Pod overhead is added on top.
 func CollectPodResources(templateSpec map[string]interface{}, replicas int32, name string, namespace string) (*api.PodResources, error) {
-
-	objectsWithResources := make([]interface{}, 0)
-	containers, containersFound, err := unstructured.NestedSlice(templateSpec, "containers")
+	containers, _, err := unstructured.NestedSlice(templateSpec, "containers")
 	if err != nil {
 		return nil, err
 	}
-	if containersFound {
-		objectsWithResources = append(objectsWithResources, containers...)
-	}
-	initContainers, initContainersFound, err := unstructured.NestedSlice(templateSpec, "initContainers")
+	initContainers, _, err := unstructured.NestedSlice(templateSpec, "initContainers")
 	if err != nil {
 		return nil, err
 	}
-	if initContainersFound {
-		objectsWithResources = append(objectsWithResources, initContainers...)
-	}
 
 	requests := map[string]*resource.Quantity{}
 	limits := map[string]*resource.Quantity{}
-	if err := CollectResources(objectsWithResources, 1, &requests, &limits); err != nil {
-		return nil, err
+	for _, container := range containers {
+		containerRequests, containerLimits, err := containerResources(container)
+		if err != nil {
+			return nil, err
+		}
+		addQuantities(requests, containerRequests)
+		addQuantities(limits, containerLimits)
 	}
-	Limits := map[string]string{}
-	for k, v := range limits {
-		Limits[k] = v.String()
+
+	sidecarRequests := map[string]*resource.Quantity{}
+	sidecarLimits := map[string]*resource.Quantity{}
+	initRequests := map[string]*resource.Quantity{}
+	initLimits := map[string]*resource.Quantity{}
+	for _, container := range initContainers {
+		containerRequests, containerLimits, err := containerResources(container)
+		if err != nil {
+			return nil, err
+		}
+		if isSidecar(container) {
+			addQuantities(sidecarRequests, containerRequests)
+			addQuantities(sidecarLimits, containerLimits)
+			maxQuantities(initRequests, sidecarRequests)
+			maxQuantities(initLimits, sidecarLimits)
+			continue
+		}
+		addQuantities(containerRequests, sidecarRequests)
+		addQuantities(containerLimits, sidecarLimits)
+		maxQuantities(initRequests, containerRequests)
+		maxQuantities(initLimits, containerLimits)
 	}
-	Requests := map[string]string{}
-	for k, v := range requests {
-		Requests[k] = v.String()
+	addQuantities(requests, sidecarRequests)
+	addQuantities(limits, sidecarLimits)
+	maxQuantities(requests, initRequests)
+	maxQuantities(limits, initLimits)
+
+	if overhead, found, _ := unstructured.NestedMap(templateSpec, "overhead"); found {
+		overheadQuantities := map[string]*resource.Quantity{}
+		if err := addResources(&overheadQuantities, overhead, 1); err != nil {
+			return nil, err
+		}
+		addQuantities(requests, overheadQuantities)
+		for k, v := range overheadQuantities {
+			// overhead only counts towards limits which are set
+			if current, exists := limits[k]; exists {
+				current.Add(*v)
+			}
+		}
 	}
 
 	totals := api.PodResources{
 		Id:       api.ResourceId{Name: name, Namespace: namespace},
-		Limits:   Limits,
+		Limits:   toStrings(limits),
 		Replica:  replicas,
-		Requests: Requests,
+		Requests: toStrings(requests),
 	}
 	return &totals, nil
 }
 
+// containerResources returns the requests and limits of a container.
+// Requests not set explicitly default to the limits as they do in Kubernetes.
+func containerResources(container interface{}) (map[string]*resource.Quantity, map[string]*resource.Quantity, error) {
+	requests := map[string]*resource.Quantity{}
+	limits := map[string]*resource.Quantity{}
+	if err := CollectResources([]interface{}{container}, 1, &requests, &limits); err != nil {
+		return nil, nil, err
+	}
+	for k, v := range limits {
+		if _, exists := requests[k]; !exists {
+			requests[k] = copyQuantity(v)
+		}
+	}
+	return requests, limits, nil
+}
+
+func isSidecar(container interface{}) bool {
+	containerMap, ok := container.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	restartPolicy, _, _ := unstructured.NestedString(containerMap, "restartPolicy")
+	return restartPolicy == "Always"
+}
+
+func addQuantities(totals map[string]*resource.Quantity, values map[string]*resource.Quantity) {
+	for k, v := range values {
+		if current, exists := totals[k]; exists {
+			current.Add(*v)
+		} else {
+			totals[k] = copyQuantity(v)
+		}
+	}
+}
+
+func maxQuantities(totals map[string]*resource.Quantity, values map[string]*resource.Quantity) {
+	for k, v := range values {
+		if current, exists := totals[k]; !exists || current.Cmp(*v) < 0 {
+			totals[k] = copyQuantity(v)
+		}
+	}
+}
+
+func copyQuantity(quantity *resource.Quantity) *resource.Quantity {
+	copied := quantity.DeepCopy()
+	return &copied
+}
+
+func toStrings(quantities map[string]*resource.Quantity) map[string]string {
+	result := map[string]string{}
+	for k, v := range quantities {
+		result[k] = v.String()
+	}
+	return result
+}
+
 func CollectResources(object []interface{}, replicas int64, totalRequests *map[string]*resource.Quantity, totalLimits *map[string]*resource.Quantity) error {
 	if object == nil {
 		return nil
@@ -166,7 +302,7 @@ func addResources(totals *map[string]*resource.Quantity, input interface{}, repl
 	}
 
 	for k, v := range resMap {
-		valStr, ok := v.(string)
+		valStr, ok := quantityString(v)
 		if !ok {
 			continue
 		}
@@ -193,3 +329,19 @@ func addResources(totals *map[string]*resource.Quantity, input interface{}, repl
 	}
 	return nil
 }
+
+// quantityString returns the quantity as string, quantities like GPU counts are often given as numbers
+func quantityString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case int:
+		return strconv.Itoa(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"hiro.io/anyapplication/internal/httpapi/api"
+	"hiro.io/anyapplication/internal/podtemplate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/yaml"
 )
@@ -37,7 +40,7 @@ spec:
 	err := yaml.Unmarshal([]byte(yamlString), u)
 	assert.NoError(t, err)
 
-	est := NewWorkloadParser()
+	est := NewWorkloadParser(podtemplate.DefaultRegistry(), nil)
 	totals, pvc, err := est.Parse(u)
 	assert.NoError(t, err)
 
@@ -80,7 +83,7 @@ spec:
 	err := yaml.Unmarshal([]byte(yamlString), u)
 	assert.NoError(t, err)
 
-	est := NewWorkloadParser()
+	est := NewWorkloadParser(podtemplate.DefaultRegistry(), nil)
 	totals, pvc, err := est.Parse(u)
 	assert.NoError(t, err)
 
@@ -88,7 +91,7 @@ spec:
 		Id:       api.ResourceId{Name: "test", Namespace: "ns"},
 		Limits:   map[string]string{},
 		Replica:  1,
-		Requests: map[string]string{"cpu": "200m", "memory": "320Mi"},
+		Requests: map[string]string{"cpu": "150m", "memory": "256Mi"},
 	}, totals)
 
 	assert.Equal(t, []api.PVCResources(nil), pvc)
@@ -139,7 +142,7 @@ spec:
 	err := yaml.Unmarshal([]byte(yamlString), u)
 	assert.NoError(t, err)
 
-	est := NewWorkloadParser()
+	est := NewWorkloadParser(podtemplate.DefaultRegistry(), nil)
 	totals, pvc, err := est.Parse(u)
 	assert.NoError(t, err)
 
@@ -147,7 +150,7 @@ spec:
 		Id:       api.ResourceId{Name: "web", Namespace: ""},
 		Limits:   map[string]string{},
 		Replica:  3,
-		Requests: map[string]string{"cpu": "200m", "memory": "320Mi"},
+		Requests: map[string]string{"cpu": "150m", "memory": "256Mi"},
 	}, totals)
 
 	assert.Equal(t, []api.PVCResources{
@@ -166,3 +169,168 @@ spec:
 	}, pvc)
 
 }
+
+func TestEstimateFromYAML_CronJob(t *testing.T) {
+	yamlString := `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: test
+  namespace: ns
+spec:
+  schedule: "* * * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: app
+              resources:
+                requests:
+                  cpu: "100m"
+                  memory: "128Mi"
+`
+	u := &unstructured.Unstructured{}
+	err := yaml.Unmarshal([]byte(yamlString), u)
+	assert.NoError(t, err)
+
+	est := NewWorkloadParser(podtemplate.DefaultRegistry(), nil)
+	totals, _, err := est.Parse(u)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"cpu": "100m", "memory": "128Mi"}, totals.Requests)
+}
+
+func parseWorkload(t *testing.T, yamlString string, cluster *ClusterInfo) (*api.PodResources, []api.PVCResources) {
+	u := &unstructured.Unstructured{}
+	assert.NoError(t, yaml.Unmarshal([]byte(yamlString), u))
+
+	est := NewWorkloadParser(podtemplate.DefaultRegistry(), cluster)
+	totals, pvc, err := est.Parse(u)
+	assert.NoError(t, err)
+	return totals, pvc
+}
+
+func TestEstimateFromYAML_SidecarsOverheadAndExtendedResources(t *testing.T) {
+	totals, _ := parseWorkload(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  template:
+    spec:
+      overhead:
+        cpu: 50m
+        memory: 32Mi
+      initContainers:
+        - name: sidecar
+          restartPolicy: Always
+          resources:
+            requests:
+              cpu: 100m
+              memory: 64Mi
+        - name: migrate
+          resources:
+            requests:
+              cpu: "1"
+              memory: 64Mi
+      containers:
+        - name: app
+          resources:
+            requests:
+              cpu: 200m
+              memory: 256Mi
+            limits:
+              memory: 512Mi
+              nvidia.com/gpu: 1
+`, nil)
+
+	assert.Equal(t, map[string]string{
+		"cpu":            "1150m",
+		"memory":         "352Mi",
+		"nvidia.com/gpu": "1",
+	}, totals.Requests)
+	assert.Equal(t, map[string]string{
+		"memory":         "544Mi",
+		"nvidia.com/gpu": "1",
+	}, totals.Limits)
+}
+
+func TestEstimateFromYAML_DaemonSetRunsOnMatchingNodes(t *testing.T) {
+	node := func(name string, labels map[string]string, unschedulable bool) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		}
+	}
+	cluster := &ClusterInfo{Nodes: []corev1.Node{
+		node("edge-1", map[string]string{"role": "edge"}, false),
+		node("edge-2", map[string]string{"role": "edge"}, false),
+		node("edge-3", map[string]string{"role": "edge"}, true),
+		node("core-1", map[string]string{"role": "core"}, false),
+	}}
+
+	totals, _ := parseWorkload(t, `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      nodeSelector:
+        role: edge
+      containers:
+        - name: agent
+`, cluster)
+	assert.Equal(t, int32(2), totals.Replica)
+
+	totals, _ = parseWorkload(t, `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      containers:
+        - name: agent
+`, nil)
+	assert.Equal(t, int32(1), totals.Replica)
+}
+
+func TestEstimateFromYAML_JobParallelism(t *testing.T) {
+	totals, _ := parseWorkload(t, `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+spec:
+  parallelism: 4
+  completions: 2
+  template:
+    spec:
+      containers:
+        - name: job
+`, nil)
+	assert.Equal(t, int32(2), totals.Replica)
+
+	totals, _ = parseWorkload(t, `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cron
+spec:
+  schedule: "* * * * *"
+  jobTemplate:
+    spec:
+      parallelism: 3
+      template:
+        spec:
+          containers:
+            - name: job
+`, nil)
+	assert.Equal(t, int32(3), totals.Replica)
+}
+
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package restmapping
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/argoproj/gitops-engine/pkg/cache"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/cockroachdb/errors"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+const crdGroup = "apiextensions.k8s.io"
+
+// CachedMapper is a discovery backed REST mapper that keeps discovery results in memory.
+// It is shared by the Helm client, which needs the scope of kinds to namespace rendered
+// resources, and the controller manager. The cache is dropped by Invalidate, which happens
+// automatically when CRDs observed by the cluster cache change.
+type CachedMapper struct {
+	discovery discovery.CachedDiscoveryInterface
+	mapper    *restmapper.DeferredDiscoveryRESTMapper
+	log       logr.Logger
+
+	mu     sync.Mutex
+	scopes map[schema.GroupVersionKind]bool
+}
+
+func NewCachedMapper(cfg *rest.Config, log logr.Logger) (*CachedMapper, error) {
+	disc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create discovery client")
+	}
+	return NewCachedMapperForDiscovery(disc, log), nil
+}
+
+func NewCachedMapperForDiscovery(disc discovery.DiscoveryInterface, log logr.Logger) *CachedMapper {
+	cachedDiscovery := memory.NewMemCacheClient(disc)
+	return &CachedMapper{
+		discovery: cachedDiscovery,
+		mapper:    restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
+		log:       log.WithName("RESTMapper"),
+	}
+}
+
+// RESTMapper returns the mapper sharing the discovery cache
+func (m *CachedMapper) RESTMapper() meta.RESTMapper {
+	return m.mapper
+}
+
+// ClusterScopeMap returns a map where true = cluster-scoped, false = namespaced.
+// The map is built once from discovery and reused until the mapper is invalidated.
+func (m *CachedMapper) ClusterScopeMap() (map[schema.GroupVersionKind]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.scopes != nil {
+		return m.scopes, nil
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(m.discovery)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get API group resources")
+	}
+	m.scopes = buildClusterScopeMap(groupResources)
+	return m.scopes, nil
+}
+
+// Invalidate drops cached discovery results, they are fetched again on next use
+func (m *CachedMapper) Invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.scopes = nil
+	m.mapper.Reset()
+	m.log.V(1).Info("Discovery cache invalidated")
+}
+
+// InvalidateOnCRDChanges invalidates the mapper whenever the cluster cache observes a CRD change
+func (m *CachedMapper) InvalidateOnCRDChanges(clusterCache cache.ClusterCache) cache.Unsubscribe {
+	return clusterCache.OnResourceUpdated(func(newRes *cache.Resource, oldRes *cache.Resource, _ map[kube.ResourceKey]*cache.Resource) {
+		if isCRD(newRes) || isCRD(oldRes) {
+			m.Invalidate()
+		}
+	})
+}
+
+func isCRD(res *cache.Resource) bool {
+	return res != nil && res.Ref.Kind == kube.CustomResourceDefinitionKind && strings.HasPrefix(res.Ref.APIVersion, crdGroup+"/")
+}
+
+func buildClusterScopeMap(groupResources []*restmapper.APIGroupResources) map[schema.GroupVersionKind]bool {
+	result := make(map[schema.GroupVersionKind]bool)
+
+	for _, group := range groupResources {
+		for version, resources := range group.VersionedResources {
+			gv := schema.GroupVersion{Group: group.Group.Name, Version: version}
+			for _, r := range resources {
+				// skip subresources
+				if strings.Contains(r.Name, "/") {
+					continue
+				}
+				result[gv.WithKind(r.Kind)] = !r.Namespaced
+			}
+		}
+	}
+	return result
+}
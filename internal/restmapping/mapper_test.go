@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package restmapping
+
+import (
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("CachedMapper", func() {
+	var (
+		discovery *fakediscovery.FakeDiscovery
+		mapper    *CachedMapper
+	)
+
+	BeforeEach(func() {
+		discovery = &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}
+		discovery.Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: "v1",
+				APIResources: []metav1.APIResource{
+					{Name: "pods", Kind: "Pod", Namespaced: true},
+					{Name: "pods/status", Kind: "Pod", Namespaced: true},
+					{Name: "namespaces", Kind: "Namespace", Namespaced: false},
+				},
+			},
+		}
+		mapper = NewCachedMapperForDiscovery(discovery, logr.Discard())
+	})
+
+	It("should build cluster scope map from discovery", func() {
+		scopes, err := mapper.ClusterScopeMap()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scopes).To(Equal(map[schema.GroupVersionKind]bool{
+			{Version: "v1", Kind: "Pod"}:       false,
+			{Version: "v1", Kind: "Namespace"}: true,
+		}))
+	})
+
+	It("should reuse discovery results until invalidated", func() {
+		_, err := mapper.ClusterScopeMap()
+		Expect(err).NotTo(HaveOccurred())
+
+		discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "widgets", Kind: "Widget", Namespaced: true},
+			},
+		})
+		widget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+		scopes, err := mapper.ClusterScopeMap()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scopes).NotTo(HaveKey(widget))
+
+		mapper.Invalidate()
+
+		scopes, err = mapper.ClusterScopeMap()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scopes).To(HaveKeyWithValue(widget, false))
+
+		mapping, err := mapper.RESTMapper().RESTMapping(widget.GroupKind(), widget.Version)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mapping.Resource.Resource).To(Equal("widgets"))
+	})
+})
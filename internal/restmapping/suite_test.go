@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2025 HIRO-MicroDataCenters BV affiliate company and DCP contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package restmapping
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRestMapping(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RESTMapping Suite")
+}